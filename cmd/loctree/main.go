@@ -6,27 +6,284 @@ import (
 	
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/user/loctree/internal/cli"
+	"github.com/user/loctree/internal/configfile"
+	"github.com/user/loctree/internal/gitfs"
+	"github.com/user/loctree/internal/history"
+	"github.com/user/loctree/internal/report"
+	"github.com/user/loctree/internal/tree"
 	"github.com/user/loctree/internal/ui"
 )
 
 func main() {
-	// Parse command-line arguments
-	path, err := cli.ParseArgs(os.Args[1:])
+	// Read the config files, which set the theme, key bindings and defaults
+	// of the command-line options
+	args := os.Args[1:]
+	file, err := configfile.Load(configDir(args))
 	if err != nil {
-		fmt.Fprintf(os.Stderr, "%v\n", err)
+		fmt.Fprintf(os.Stderr, "Error reading config: %v\n", err)
+		os.Exit(1)
+	}
+	if err := applyConfig(file); err != nil {
+		fmt.Fprintf(os.Stderr, "Error in config: %v\n", err)
 		os.Exit(1)
 	}
 	
-	// Validate the path
-	err = cli.ValidatePath(path)
+	// Parse command-line arguments
+	config, err := cli.ParseArgsWithDefaults(args, file.Defaults)
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "%v\n", err)
 		os.Exit(1)
 	}
 	
+	buildOpts := tree.Options{Jobs: config.Jobs, Filter: &config.Filter}
+	reportOpts := report.Options{
+		IncludeFiles: config.ShowFiles,
+		MaxDepth:     config.Depth,
+		Metric:       config.Metric,
+		Sort:         config.Sort,
+	}
+	uiOpts := ui.Options{
+		Build:     buildOpts,
+		ShowFiles: config.ShowFiles,
+		Columns:   config.Columns,
+		Metric:    config.Metric,
+		Sort:      config.Sort,
+		Depth:     config.Depth,
+		Watch:     config.Watch,
+		Keys:      file.Keys,
+	}
+	
+	switch config.Command {
+	case cli.CommandSnapshot:
+		runSnapshot(config, buildOpts)
+		return
+	case cli.CommandDiff:
+		runDiff(config, buildOpts, reportOpts, uiOpts)
+		return
+	case cli.CommandHistory:
+		runHistory(config, buildOpts, reportOpts, uiOpts)
+		return
+	}
+	
+	// Validate the path (a revision is checked when it is opened)
+	if config.Rev == "" {
+		err = cli.ValidatePath(config.Path)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "%v\n", err)
+			os.Exit(1)
+		}
+	}
+	
+	// Scan the git revision instead of the working copy, keeping it open
+	// until the report is written or the TUI exits, as the TUI rescans it
+	if config.Rev != "" {
+		fsys := openRevision(config.Path, config.Rev)
+		defer fsys.Close()
+		buildOpts.FS = fsys
+		uiOpts.Build.FS = fsys
+	}
+	
+	// Write a report instead of starting the TUI
+	if config.Format != report.FormatTUI {
+		root := buildTree(config.Path, buildOpts)
+		err = report.Write(os.Stdout, root, config.Format, reportOpts)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error writing report: %v\n", err)
+			os.Exit(1)
+		}
+		return
+	}
+	
 	// Create and run TUI with loading screen
-	model := ui.NewLoadingModel(path)
-	p := tea.NewProgram(model, tea.WithAltScreen())
+	runTUI(ui.NewLoadingModel(config.Path, uiOpts))
+}
+
+// configDir returns where the per-repository config file is looked up: the
+// path being scanned, or the current directory when the arguments only
+// parse with defaults from the config files
+func configDir(args []string) string {
+	config, err := cli.ParseArgs(args)
+	if err != nil {
+		return "."
+	}
+	return config.Path
+}
+
+// applyConfig sets the theme of the TUI and checks the key bindings of a
+// config file
+func applyConfig(file *configfile.File) error {
+	theme, err := ui.LoadTheme(file.Theme, file.Colors)
+	if err != nil {
+		return err
+	}
+	ui.SetTheme(theme)
+	return ui.CheckKeys(file.Keys)
+}
+
+// runSnapshot scans a directory and writes it, files included, as a JSON
+// document that diff can compare against later
+func runSnapshot(config *cli.Config, buildOpts tree.Options) {
+	var root *tree.DirectoryNode
+	if config.Rev != "" {
+		root = buildRevision(config.Path, config.Rev, buildOpts)
+	} else {
+		if err := cli.ValidatePath(config.Path); err != nil {
+			fmt.Fprintf(os.Stderr, "%v\n", err)
+			os.Exit(1)
+		}
+		root = buildTree(config.Path, buildOpts)
+	}
+	
+	out := os.Stdout
+	if config.Output != "" {
+		file, err := os.Create(config.Output)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error creating snapshot: %v\n", err)
+			os.Exit(1)
+		}
+		out = file
+	}
+	
+	err := report.WriteJSON(out, root, report.Options{IncludeFiles: true, MaxDepth: tree.AllDepths})
+	if out != os.Stdout {
+		// Writes may only fail on close, leaving the snapshot truncated
+		if closeErr := out.Close(); err == nil {
+			err = closeErr
+		}
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error writing snapshot: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+// runDiff compares a snapshot or git revision with another snapshot, git
+// revision or a fresh scan of a directory, either in the TUI or as a report
+func runDiff(config *cli.Config, buildOpts tree.Options, reportOpts report.Options, uiOpts ui.Options) {
+	var base *tree.DirectoryNode
+	baseName := config.Base
+	if config.From != "" {
+		base = buildRevision(config.Path, config.From, buildOpts)
+		baseName = config.Path + "@" + config.From
+	} else {
+		var err error
+		base, err = report.LoadSnapshot(config.Base)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error reading snapshot: %v\n", err)
+			os.Exit(1)
+		}
+	}
+	uiOpts.Base = base
+	
+	var root *tree.DirectoryNode
+	if config.To != "" {
+		root = buildRevision(config.Path, config.To, buildOpts)
+	} else {
+		info, err := os.Stat(config.Path)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: Path does not exist: %s\n", config.Path)
+			os.Exit(1)
+		}
+		
+		// Scan a directory behind the loading screen
+		if info.IsDir() && config.Format == report.FormatTUI {
+			runTUI(ui.NewLoadingModel(config.Path, uiOpts))
+			return
+		}
+		
+		if config.Watch {
+			fmt.Fprintf(os.Stderr, "Error: --watch requires a directory, not a snapshot\n")
+			os.Exit(1)
+		}
+		if info.IsDir() {
+			root = buildTree(config.Path, buildOpts)
+		} else if root, err = report.LoadSnapshot(config.Path); err != nil {
+			fmt.Fprintf(os.Stderr, "Error reading snapshot: %v\n", err)
+			os.Exit(1)
+		}
+	}
+	
+	if config.Format == report.FormatTUI {
+		runTUI(ui.NewModelWithOptions(root, uiOpts))
+		return
+	}
+	
+	err := report.WriteDiff(os.Stdout, tree.Diff(base, root), baseName, config.Format, reportOpts)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error writing report: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+// runHistory samples the git history of a directory and shows it as
+// sparklines in the TUI or writes the time series as a report
+func runHistory(config *cli.Config, buildOpts tree.Options, reportOpts report.Options, uiOpts ui.Options) {
+	h, err := history.Build(config.Path, history.Options{
+		Rev:    config.Rev,
+		Since:  config.Since,
+		Step:   config.Step,
+		Metric: config.Metric,
+		Build:  buildOpts,
+		Progress: func(done, total int) {
+			fmt.Fprintf(os.Stderr, "\rScanning commit %d/%d", done, total)
+		},
+	})
+	fmt.Fprintln(os.Stderr)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error reading history: %v\n", err)
+		os.Exit(1)
+	}
+	
+	if config.Format == report.FormatTUI {
+		// Show the tree at the revision whose history was sampled
+		if config.Rev != "" {
+			fsys := openRevision(config.Path, config.Rev)
+			defer fsys.Close()
+			uiOpts.Build.FS = fsys
+		}
+		uiOpts.History = h
+		runTUI(ui.NewLoadingModel(config.Path, uiOpts))
+		return
+	}
+	
+	if err := report.WriteHistory(os.Stdout, h, config.Format, reportOpts); err != nil {
+		fmt.Fprintf(os.Stderr, "Error writing report: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+// buildTree scans a directory, exiting on failure
+func buildTree(path string, opts tree.Options) *tree.DirectoryNode {
+	root, err := tree.BuildTreeWithOptions(path, opts)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error scanning directory: %v\n", err)
+		os.Exit(1)
+	}
+	return root
+}
+
+// openRevision opens a git revision of a directory, exiting on failure
+func openRevision(path, rev string) *gitfs.FS {
+	fsys, err := gitfs.Open(path, rev)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+	return fsys
+}
+
+// buildRevision scans a directory as it was at a git revision
+func buildRevision(path, rev string, opts tree.Options) *tree.DirectoryNode {
+	fsys := openRevision(path, rev)
+	defer fsys.Close()
+	
+	opts.FS = fsys
+	return buildTree(path, opts)
+}
+
+// runTUI runs the interactive program until the user quits
+func runTUI(model tea.Model) {
+	p := tea.NewProgram(model, tea.WithAltScreen(), tea.WithMouseCellMotion())
 	
 	if _, err := p.Run(); err != nil {
 		fmt.Fprintf(os.Stderr, "Error running TUI: %v\n", err)
@@ -1,21 +1,398 @@
 package cli
 
 import (
+	"flag"
 	"fmt"
+	"io"
 	"os"
+	"runtime"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+	
+	"github.com/user/loctree/internal/filter"
+	"github.com/user/loctree/internal/format"
+	"github.com/user/loctree/internal/history"
+	"github.com/user/loctree/internal/report"
+	"github.com/user/loctree/internal/tree"
+)
+
+// usage is the usage summary shown on argument errors
+const usage = `Usage: loctree [options] <directory_path>
+       loctree --rev <revision> [options] [directory_path]
+       loctree snapshot [options] <directory_path> [-o snap.json]
+       loctree diff [options] <old.json> <new.json|directory_path>
+       loctree diff --from <revision> [--to <revision>] [options] [directory_path]
+       loctree history [--since 6m] [--step weekly] [options] <directory_path>`
+
+// Subcommands. Without one, loctree scans a directory.
+const (
+	CommandSnapshot = "snapshot" // Write a JSON snapshot for later comparison
+	CommandDiff     = "diff"     // Compare a snapshot with another snapshot or directory
+	CommandHistory  = "history"  // Sample line counts over the git history
 )
 
-// ParseArgs parses command-line arguments and returns the directory path
-func ParseArgs(args []string) (string, error) {
-	if len(args) == 0 {
-		return "", fmt.Errorf("Usage: loctree <directory_path>")
+// Config holds the parsed command-line options
+type Config struct {
+	Command   string         // Subcommand, empty for a plain scan
+	Path      string         // Directory to scan, or for diff the snapshot or directory compared
+	Base      string         // Snapshot to compare against (diff only)
+	Output    string         // File the snapshot is written to; empty for stdout (snapshot only)
+	Rev       string         // Git revision to scan instead of the working copy
+	From      string         // Git revision to compare against (diff only)
+	To        string         // Git revision compared; empty for the working copy (diff only)
+	Since     time.Time      // First history sample (history only)
+	Step      history.Step   // Interval between history samples (history only)
+	Jobs      int            // Number of files counted in parallel
+	Filter    filter.Filter  // Include/exclude rules shared by every scan
+	ShowFiles bool           // Show files as leaves in the tree by default
+	Format    string         // Output format: the interactive TUI or a report written to stdout
+	Depth     int            // Expand the tree this many levels; tree.AllDepths when not set, which lists every level in reports and leaves the TUI collapsed
+	Metric    tree.Metric    // Line count that drives display and sort order
+	Sort      tree.Order     // Order of siblings in the tree
+	Columns   format.Columns // Optional columns shown next to each row of the TUI
+	Watch     bool           // Update the TUI as files change on disk
+}
+
+// stringList is a repeatable string flag
+type stringList struct {
+	values *[]string
+	split  bool // Also split each value on commas
+}
+
+func (l stringList) String() string {
+	if l.values == nil {
+		return ""
+	}
+	return strings.Join(*l.values, ",")
+}
+
+func (l stringList) Set(value string) error {
+	if !l.split {
+		*l.values = append(*l.values, value)
+		return nil
+	}
+	for _, part := range strings.Split(value, ",") {
+		if part = strings.TrimSpace(part); part != "" {
+			*l.values = append(*l.values, part)
+		}
+	}
+	return nil
+}
+
+// depthValue is the --depth flag, which stays tree.AllDepths until it is set
+type depthValue struct {
+	depth *int
+}
+
+func (d depthValue) String() string {
+	if d.depth == nil || *d.depth < 0 {
+		return ""
+	}
+	return strconv.Itoa(*d.depth)
+}
+
+func (d depthValue) Set(value string) error {
+	depth, err := strconv.Atoi(value)
+	if err != nil {
+		return err
+	}
+	if depth < 0 {
+		return fmt.Errorf("must not be negative")
+	}
+	*d.depth = depth
+	return nil
+}
+
+// ParseArgs parses command-line arguments into a Config.
+// Options may appear before or after the directory path.
+func ParseArgs(args []string) (*Config, error) {
+	return ParseArgsWithDefaults(args, nil)
+}
+
+// ParseArgsWithDefaults parses command-line arguments into a Config, taking
+// options the command line leaves unset from defaults, by flag name, as
+// read from a config file. A list replaces every value of a repeatable
+// option. Defaults that do not apply to the command or format, like -o
+// outside snapshot, are skipped.
+func ParseArgsWithDefaults(args []string, defaults map[string]any) (*Config, error) {
+	config := &Config{}
+	
+	if len(args) > 0 && (args[0] == CommandSnapshot || args[0] == CommandDiff || args[0] == CommandHistory) {
+		config.Command = args[0]
+		args = args[1:]
+	}
+	
+	fs := flag.NewFlagSet("loctree", flag.ContinueOnError)
+	fs.SetOutput(io.Discard)
+	fs.IntVar(&config.Jobs, "jobs", runtime.GOMAXPROCS(0), "number of files counted in parallel")
+	fs.BoolVar(&config.ShowFiles, "files", false, "show files as leaves in the tree")
+	fs.StringVar(&config.Format, "format", report.FormatTUI, "output format: "+strings.Join(report.Formats, ", "))
+	config.Depth = tree.AllDepths
+	fs.Var(depthValue{depth: &config.Depth}, "depth", "expand the tree this many `levels`, 0 showing only the root (by default reports list every level and the TUI starts collapsed)")
+	metric := fs.String("metric", tree.MetricLines.String(), "line count to show and sort by: lines, code, comments or blanks")
+	columns := fs.String("columns", "", "columns shown next to each row: parent, root, files and bar, comma-separated")
+	sortOrder := fs.String("sort", "", "order siblings by loc, name, files, language or change (diff only), optionally followed by :asc or :desc (default loc, or change for diff)")
+	fs.BoolVar(&config.Watch, "watch", false, "update the tree as files change (TUI of the working copy only)")
+	fs.StringVar(&config.Output, "o", "", "write the snapshot to this file instead of stdout (snapshot only)")
+	fs.StringVar(&config.Rev, "rev", "", "scan this git revision instead of the working copy")
+	fs.StringVar(&config.From, "from", "", "git revision to compare against (diff only)")
+	fs.StringVar(&config.To, "to", "", "git revision to compare, instead of the working copy (diff only)")
+	since := fs.String("since", "6m", "history period, e.g. 30d, 2w, 6m, 1y or 2024-01-31 (history only)")
+	step := fs.String("step", history.Weekly.String(), "interval between history samples: daily, weekly or monthly (history only)")
+	fs.BoolVar(&config.Filter.NoIgnore, "no-ignore", false, "do not respect .gitignore, .ignore and .loctreeignore files")
+	fs.Var(stringList{values: &config.Filter.Include}, "include", "only count files matching this glob (repeatable)")
+	fs.Var(stringList{values: &config.Filter.Exclude}, "exclude", "skip files or directories matching this glob (repeatable)")
+	fs.Var(stringList{values: &config.Filter.Extensions, split: true}, "ext", "only count files with these extensions, e.g. go,proto (repeatable)")
+	fs.Var(stringList{values: &config.Filter.ExcludeDirs}, "exclude-dir", "skip directories with this name or relative path (repeatable)")
+	
+	// Parse flags interspersed with positional arguments
+	var positional []string
+	for {
+		if err := fs.Parse(args); err != nil {
+			if err == flag.ErrHelp {
+				return nil, fmt.Errorf("%s", usageText(fs))
+			}
+			return nil, fmt.Errorf("%v\n%s", err, usageText(fs))
+		}
+		args = fs.Args()
+		if len(args) == 0 {
+			break
+		}
+		positional = append(positional, args[0])
+		args = args[1:]
+	}
+	if err := setDefaults(fs, config, len(positional), defaults); err != nil {
+		return nil, err
+	}
+	
+	if err := checkRevisions(config); err != nil {
+		return nil, err
+	}
+	
+	// Revisions are read from the repository in the current directory by default
+	minArgs, maxArgs := 1, 1
+	switch {
+	case config.Command == CommandDiff && config.From != "":
+		minArgs = 0
+	case config.Command == CommandDiff:
+		minArgs, maxArgs = 2, 2
+	case config.Rev != "":
+		minArgs = 0
+	}
+	
+	if len(positional) == 0 && minArgs > 0 {
+		return nil, fmt.Errorf("%s", usageText(fs))
+	}
+	if len(positional) > maxArgs || len(positional) < minArgs {
+		switch {
+		case minArgs == 0:
+			return nil, fmt.Errorf("Expected at most one argument, got %d", len(positional))
+		case maxArgs == 1:
+			return nil, fmt.Errorf("Expected exactly one argument, got %d", len(positional))
+		}
+		return nil, fmt.Errorf("Expected exactly %d arguments, got %d", maxArgs, len(positional))
+	}
+	if len(positional) < maxArgs {
+		positional = append(positional, ".")
+	}
+	
+	if config.Jobs < 1 {
+		return nil, fmt.Errorf("Error: --jobs must be at least 1, got %d", config.Jobs)
+	}
+	
+	if !report.IsFormat(config.Format) {
+		return nil, fmt.Errorf("Error: unknown format %q (expected %s)", config.Format, strings.Join(report.Formats, ", "))
+	}
+	if config.Command == CommandDiff && !report.IsDiffFormat(config.Format) {
+		return nil, fmt.Errorf("Error: diff does not support format %q (expected %s)", config.Format, strings.Join(report.DiffFormats, ", "))
+	}
+	if config.Command == CommandHistory && !report.IsHistoryFormat(config.Format) {
+		return nil, fmt.Errorf("Error: history does not support format %q (expected %s)", config.Format, strings.Join(report.HistoryFormats, ", "))
+	}
+	if config.Output != "" && config.Command != CommandSnapshot {
+		return nil, fmt.Errorf("Error: -o is only supported by the snapshot command")
+	}
+	
+	if err := checkWatch(config); err != nil {
+		return nil, err
+	}
+	
+	parsedMetric, err := tree.ParseMetric(*metric)
+	if err != nil {
+		return nil, fmt.Errorf("Error: %v", err)
+	}
+	config.Metric = parsedMetric
+	
+	if config.Sort, err = parseSort(*sortOrder, config.Command); err != nil {
+		return nil, err
+	}
+	if config.Columns, err = format.ParseColumns(*columns); err != nil {
+		return nil, fmt.Errorf("Error: %v", err)
+	}
+	
+	if config.Since, err = history.ParseSince(*since, time.Now()); err != nil {
+		return nil, fmt.Errorf("Error: %v", err)
+	}
+	if config.Step, err = history.ParseStep(*step); err != nil {
+		return nil, fmt.Errorf("Error: %v", err)
+	}
+	
+	if config.Command == CommandDiff && config.From == "" {
+		config.Base = positional[0]
+		positional = positional[1:]
+	}
+	config.Path = positional[0]
+	return config, nil
+}
+
+// setDefaults sets the options that were not given on the command line
+// from defaults, where they apply to config and the number of positional
+// arguments as parsed so far
+func setDefaults(fs *flag.FlagSet, config *Config, positional int, defaults map[string]any) error {
+	given := map[string]bool{}
+	fs.Visit(func(f *flag.Flag) {
+		given[f.Name] = true
+	})
+	
+	names := make([]string, 0, len(defaults))
+	for name := range defaults {
+		names = append(names, name)
 	}
+	// Sorted, so from comes before to; watch depends on the format and
+	// revisions, so it comes last
+	sort.Slice(names, func(i, j int) bool {
+		if (names[i] == "watch") != (names[j] == "watch") {
+			return names[j] == "watch"
+		}
+		return names[i] < names[j]
+	})
 	
-	if len(args) != 1 {
-		return "", fmt.Errorf("Expected exactly one argument, got %d", len(args))
+	for _, name := range names {
+		f := fs.Lookup(name)
+		if f == nil {
+			return fmt.Errorf("Error: unknown option %q in the config file", name)
+		}
+		if given[name] || !defaultApplies(config, positional, name, defaults[name]) {
+			continue
+		}
+		
+		values, isList := defaults[name].([]any)
+		if !isList {
+			values = []any{defaults[name]}
+		} else if _, repeatable := f.Value.(stringList); !repeatable {
+			return fmt.Errorf("Error: option %q in the config file takes a single value", name)
+		}
+		for _, value := range values {
+			switch value.(type) {
+			case []any, map[string]any:
+				return fmt.Errorf("Error: invalid value for option %q in the config file", name)
+			}
+			if err := fs.Set(name, fmt.Sprint(value)); err != nil {
+				return fmt.Errorf("Error: invalid value for option %q in the config file: %v", name, err)
+			}
+		}
+	}
+	return nil
+}
+
+// defaultApplies reports whether the default of an option applies to the
+// command and format of config, so a default never turns a valid command
+// line into an error. Values that are invalid everywhere still apply, so
+// they are reported.
+func defaultApplies(config *Config, positional int, name string, value any) bool {
+	text := fmt.Sprint(value)
+	switch name {
+	case "o":
+		return config.Command == CommandSnapshot
+	case "rev":
+		return config.Command != CommandDiff
+	case "from":
+		// Not when comparing a snapshot, given as a second argument
+		return config.Command == CommandDiff && positional <= 1
+	case "to":
+		return config.Command == CommandDiff && config.From != ""
+	case "format":
+		switch {
+		case !report.IsFormat(text):
+			return true
+		case config.Command == CommandDiff:
+			return report.IsDiffFormat(text)
+		case config.Command == CommandHistory:
+			return report.IsHistoryFormat(text)
+		}
+	case "sort":
+		order, err := tree.ParseOrder(text)
+		return err != nil || order.Key != tree.SortChange || config.Command == CommandDiff
+	case "watch":
+		watching := *config
+		watching.Watch = true
+		return checkWatch(&watching) == nil
+	}
+	return true
+}
+
+// checkRevisions rejects git revision options used outside their command
+func checkRevisions(config *Config) error {
+	if config.Command == CommandDiff {
+		if config.Rev != "" {
+			return fmt.Errorf("Error: use --from and --to to compare revisions")
+		}
+		if config.To != "" && config.From == "" {
+			return fmt.Errorf("Error: --to requires --from")
+		}
+		return nil
+	}
+	if config.From != "" || config.To != "" {
+		return fmt.Errorf("Error: --from and --to are only supported by the diff command")
+	}
+	return nil
+}
+
+// checkWatch rejects --watch where there is no working copy shown in the TUI
+func checkWatch(config *Config) error {
+	switch {
+	case !config.Watch:
+		return nil
+	case config.Format != report.FormatTUI:
+		return fmt.Errorf("Error: --watch is only supported by the TUI")
+	case config.Command == CommandSnapshot || config.Command == CommandHistory:
+		return fmt.Errorf("Error: --watch is not supported by the %s command", config.Command)
+	case config.Rev != "" || config.To != "":
+		return fmt.Errorf("Error: --watch requires the working copy, not a git revision")
+	}
+	return nil
+}
+
+// parseSort parses the --sort option. Diffs default to the largest change
+// first, which only they can sort by.
+func parseSort(value, command string) (tree.Order, error) {
+	if value == "" {
+		if command == CommandDiff {
+			return tree.Order{Key: tree.SortChange}, nil
+		}
+		return tree.Order{}, nil
 	}
 	
-	return args[0], nil
+	order, err := tree.ParseOrder(value)
+	if err != nil {
+		return tree.Order{}, fmt.Errorf("Error: %v", err)
+	}
+	if order.Key == tree.SortChange && command != CommandDiff {
+		return tree.Order{}, fmt.Errorf("Error: --sort change is only supported by the diff command")
+	}
+	return order, nil
+}
+
+// usageText returns the usage summary followed by the option defaults
+func usageText(fs *flag.FlagSet) string {
+	var b strings.Builder
+	b.WriteString(usage + "\n\nOptions:\n")
+	fs.SetOutput(&b)
+	fs.PrintDefaults()
+	fs.SetOutput(io.Discard)
+	return strings.TrimRight(b.String(), "\n")
 }
 
 // ValidatePath checks if the given path exists and is a directory
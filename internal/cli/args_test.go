@@ -4,7 +4,14 @@ import (
 	"io/ioutil"
 	"os"
 	"path/filepath"
+	"runtime"
+	"strings"
 	"testing"
+	"time"
+	
+	"github.com/user/loctree/internal/format"
+	"github.com/user/loctree/internal/history"
+	"github.com/user/loctree/internal/tree"
 )
 
 func TestParseArgs_NoArguments(t *testing.T) {
@@ -17,12 +24,12 @@ func TestParseArgs_NoArguments(t *testing.T) {
 
 func TestParseArgs_ValidDirectory(t *testing.T) {
 	args := []string{"/tmp"}
-	path, err := ParseArgs(args)
+	config, err := ParseArgs(args)
 	if err != nil {
-		t.Errorf("Expected no error for valid argument, got: %v", err)
+		t.Fatalf("Expected no error for valid argument, got: %v", err)
 	}
-	if path != "/tmp" {
-		t.Errorf("Expected path to be '/tmp', got: %s", path)
+	if config.Path != "/tmp" {
+		t.Errorf("Expected path to be '/tmp', got: %s", config.Path)
 	}
 }
 
@@ -34,6 +41,203 @@ func TestParseArgs_TooManyArguments(t *testing.T) {
 	}
 }
 
+func TestParseArgs_DefaultJobs(t *testing.T) {
+	config, err := ParseArgs([]string{"/tmp"})
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if config.Jobs != runtime.GOMAXPROCS(0) {
+		t.Errorf("Expected jobs to default to GOMAXPROCS (%d), got: %d", runtime.GOMAXPROCS(0), config.Jobs)
+	}
+}
+
+func TestParseArgs_JobsFlag(t *testing.T) {
+	for _, args := range [][]string{
+		{"--jobs", "3", "/tmp"},
+		{"/tmp", "--jobs=3"},
+	} {
+		config, err := ParseArgs(args)
+		if err != nil {
+			t.Fatalf("Expected no error for %v, got: %v", args, err)
+		}
+		if config.Jobs != 3 {
+			t.Errorf("Expected jobs to be 3 for %v, got: %d", args, config.Jobs)
+		}
+		if config.Path != "/tmp" {
+			t.Errorf("Expected path to be '/tmp' for %v, got: %s", args, config.Path)
+		}
+	}
+}
+
+func TestParseArgs_NoIgnoreFlag(t *testing.T) {
+	config, err := ParseArgs([]string{"/tmp"})
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if config.Filter.NoIgnore {
+		t.Error("Expected ignore files to be respected by default")
+	}
+	
+	config, err = ParseArgs([]string{"--no-ignore", "/tmp"})
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if !config.Filter.NoIgnore {
+		t.Error("Expected --no-ignore to disable ignore files")
+	}
+}
+
+func TestParseArgs_FilterFlags(t *testing.T) {
+	args := []string{
+		"--include", "*.go", "--include", "*.proto",
+		"--exclude", "*_test.go",
+		"--ext", "go,proto", "--ext", "md",
+		"--exclude-dir", "testdata",
+		"/tmp",
+	}
+	config, err := ParseArgs(args)
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	
+	f := config.Filter
+	if strings.Join(f.Include, " ") != "*.go *.proto" {
+		t.Errorf("Unexpected include patterns: %v", f.Include)
+	}
+	if strings.Join(f.Exclude, " ") != "*_test.go" {
+		t.Errorf("Unexpected exclude patterns: %v", f.Exclude)
+	}
+	if strings.Join(f.Extensions, " ") != "go proto md" {
+		t.Errorf("Unexpected extensions: %v", f.Extensions)
+	}
+	if strings.Join(f.ExcludeDirs, " ") != "testdata" {
+		t.Errorf("Unexpected excluded directories: %v", f.ExcludeDirs)
+	}
+}
+
+func TestParseArgs_FilesFlag(t *testing.T) {
+	config, err := ParseArgs([]string{"--files", "/tmp"})
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if !config.ShowFiles {
+		t.Error("Expected --files to show files by default")
+	}
+}
+
+func TestParseArgs_Format(t *testing.T) {
+	config, err := ParseArgs([]string{"/tmp"})
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if config.Format != "tui" {
+		t.Errorf("Expected format to default to 'tui', got '%s'", config.Format)
+	}
+	
+	config, err = ParseArgs([]string{"--format", "json", "/tmp"})
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if config.Format != "json" {
+		t.Errorf("Expected format 'json', got '%s'", config.Format)
+	}
+}
+
+func TestParseArgs_DepthAndMetric(t *testing.T) {
+	config, err := ParseArgs([]string{"/tmp", "--depth", "2", "--metric", "code"})
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if config.Depth != 2 {
+		t.Errorf("Expected depth 2, got %d", config.Depth)
+	}
+	if config.Metric != tree.MetricCode {
+		t.Errorf("Expected metric code, got %s", config.Metric)
+	}
+	
+	if config, err := ParseArgs([]string{"/tmp"}); err != nil || config.Depth != tree.AllDepths {
+		t.Errorf("Expected every level by default, got %v and %v", config, err)
+	}
+	if config, err := ParseArgs([]string{"--depth", "0", "/tmp"}); err != nil || config.Depth != 0 {
+		t.Errorf("Expected depth 0, got %v and %v", config, err)
+	}
+	if _, err := ParseArgs([]string{"--depth", "-1", "/tmp"}); err == nil {
+		t.Error("Expected error for negative --depth, got nil")
+	}
+	if _, err := ParseArgs([]string{"--metric", "words", "/tmp"}); err == nil {
+		t.Error("Expected error for unknown --metric, got nil")
+	}
+}
+
+func TestParseArgs_Sort(t *testing.T) {
+	config, err := ParseArgs([]string{"/tmp"})
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if config.Sort != (tree.Order{}) {
+		t.Errorf("Expected the default order, got %s", config.Sort)
+	}
+	
+	config, err = ParseArgs([]string{"--sort", "name:desc", "/tmp"})
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if config.Sort != (tree.Order{Key: tree.SortName, Reverse: true}) {
+		t.Errorf("Expected name:desc, got %s", config.Sort)
+	}
+	
+	// Diffs sort by change unless told otherwise
+	config, err = ParseArgs([]string{"diff", "old.json", "new.json"})
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if config.Sort != (tree.Order{Key: tree.SortChange}) {
+		t.Errorf("Expected change:desc for diff, got %s", config.Sort)
+	}
+	
+	if _, err := ParseArgs([]string{"--sort", "change", "/tmp"}); err == nil {
+		t.Error("Expected error for --sort change outside diff, got nil")
+	}
+	if _, err := ParseArgs([]string{"--sort", "size", "/tmp"}); err == nil {
+		t.Error("Expected error for unknown --sort, got nil")
+	}
+}
+
+func TestParseArgs_Columns(t *testing.T) {
+	config, err := ParseArgs([]string{"--columns", "root,bar", "/tmp"})
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if config.Columns != format.ColumnRoot|format.ColumnBar {
+		t.Errorf("Expected root and bar columns, got %s", config.Columns)
+	}
+	
+	if _, err := ParseArgs([]string{"--columns", "percent", "/tmp"}); err == nil {
+		t.Error("Expected error for unknown --columns, got nil")
+	}
+}
+
+func TestParseArgs_UnknownFormat(t *testing.T) {
+	_, err := ParseArgs([]string{"--format", "yaml", "/tmp"})
+	if err == nil {
+		t.Error("Expected error for unknown format, got nil")
+	}
+}
+
+func TestParseArgs_InvalidJobs(t *testing.T) {
+	_, err := ParseArgs([]string{"--jobs", "0", "/tmp"})
+	if err == nil {
+		t.Error("Expected error for --jobs 0, got nil")
+	}
+}
+
+func TestParseArgs_UnknownFlag(t *testing.T) {
+	_, err := ParseArgs([]string{"--bogus", "/tmp"})
+	if err == nil {
+		t.Error("Expected error for unknown flag, got nil")
+	}
+}
+
 func TestValidatePath_DirectoryExists(t *testing.T) {
 	// Create temp directory
 	tempDir, err := ioutil.TempDir("", "loctree_test")
@@ -69,4 +273,204 @@ func TestValidatePath_NonExistentPath(t *testing.T) {
 	if err == nil {
 		t.Error("Expected error for non-existent path, got nil")
 	}
+}
+
+func TestParseArgs_Snapshot(t *testing.T) {
+	config, err := ParseArgs([]string{"snapshot", "/tmp", "-o", "snap.json"})
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if config.Command != CommandSnapshot || config.Path != "/tmp" || config.Output != "snap.json" {
+		t.Errorf("Unexpected config: %+v", config)
+	}
+}
+
+func TestParseArgs_Diff(t *testing.T) {
+	config, err := ParseArgs([]string{"diff", "old.json", "/tmp", "--format", "text"})
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if config.Command != CommandDiff || config.Base != "old.json" || config.Path != "/tmp" {
+		t.Errorf("Unexpected config: %+v", config)
+	}
+	
+	if _, err := ParseArgs([]string{"diff", "old.json"}); err == nil {
+		t.Error("Expected error for diff with one argument, got nil")
+	}
+	if _, err := ParseArgs([]string{"diff", "old.json", "/tmp", "--format", "csv"}); err == nil {
+		t.Error("Expected error for unsupported diff format, got nil")
+	}
+}
+
+func TestParseArgs_OutputWithoutSnapshot(t *testing.T) {
+	_, err := ParseArgs([]string{"-o", "snap.json", "/tmp"})
+	if err == nil {
+		t.Error("Expected error for -o without snapshot, got nil")
+	}
+}
+
+func TestParseArgs_Revisions(t *testing.T) {
+	config, err := ParseArgs([]string{"--rev", "main"})
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if config.Rev != "main" || config.Path != "." {
+		t.Errorf("Expected revision 'main' of '.', got '%s' of '%s'", config.Rev, config.Path)
+	}
+	
+	config, err = ParseArgs([]string{"diff", "--from", "v1.2.0", "--to", "HEAD", "/tmp"})
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if config.From != "v1.2.0" || config.To != "HEAD" || config.Path != "/tmp" || config.Base != "" {
+		t.Errorf("Unexpected config: %+v", config)
+	}
+	
+	invalid := [][]string{
+		{"diff", "--to", "HEAD", "old.json", "/tmp"},
+		{"diff", "--rev", "HEAD", "old.json", "/tmp"},
+		{"--from", "HEAD", "/tmp"},
+		{"--rev", "main", "/tmp", "/var"},
+	}
+	for _, args := range invalid {
+		if _, err := ParseArgs(args); err == nil {
+			t.Errorf("Expected error for %v, got nil", args)
+		}
+	}
+}
+
+func TestParseArgs_History(t *testing.T) {
+	config, err := ParseArgs([]string{"history", "/tmp", "--since", "2w", "--step", "daily", "--format", "csv"})
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if config.Command != CommandHistory || config.Step != history.Daily || config.Path != "/tmp" {
+		t.Errorf("Unexpected config: %+v", config)
+	}
+	if age := time.Since(config.Since); age < 13*24*time.Hour || age > 15*24*time.Hour {
+		t.Errorf("Expected history to start two weeks ago, got %v", config.Since)
+	}
+	
+	invalid := [][]string{
+		{"history", "/tmp", "--since", "soon"},
+		{"history", "/tmp", "--step", "hourly"},
+		{"history", "/tmp", "--format", "markdown"},
+	}
+	for _, args := range invalid {
+		if _, err := ParseArgs(args); err == nil {
+			t.Errorf("Expected error for %v, got nil", args)
+		}
+	}
+}
+
+func TestParseArgs_Watch(t *testing.T) {
+	config, err := ParseArgs([]string{"--watch", "/tmp"})
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if !config.Watch {
+		t.Error("Expected Watch to be set")
+	}
+	
+	if _, err := ParseArgs([]string{"diff", "--watch", "old.json", "/tmp"}); err != nil {
+		t.Errorf("Expected --watch to be allowed for diff, got: %v", err)
+	}
+	
+	for _, args := range [][]string{
+		{"--watch", "--format", "json", "/tmp"},
+		{"--watch", "--rev", "main"},
+		{"diff", "--watch", "--from", "v1", "--to", "v2"},
+		{"history", "--watch", "/tmp"},
+		{"snapshot", "--watch", "/tmp"},
+	} {
+		if _, err := ParseArgs(args); err == nil {
+			t.Errorf("Expected error for %v, got nil", args)
+		}
+	}
+}
+
+func TestParseArgsWithDefaults(t *testing.T) {
+	defaults := map[string]any{
+		"metric":      "code",
+		"depth":       int64(2),
+		"files":       true,
+		"exclude-dir": []any{"vendor", "testdata"},
+		"ext":         "go,proto",
+	}
+	
+	config, err := ParseArgsWithDefaults([]string{"/tmp", "--depth", "4", "--exclude-dir", "node_modules"}, defaults)
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if config.Metric != tree.MetricCode || !config.ShowFiles {
+		t.Errorf("Expected the metric and files from the defaults, got %s and %v", config.Metric, config.ShowFiles)
+	}
+	if config.Depth != 4 {
+		t.Errorf("Expected the command line's depth 4, got %d", config.Depth)
+	}
+	if got := strings.Join(config.Filter.ExcludeDirs, ","); got != "node_modules" {
+		t.Errorf("Expected the command line to replace the excluded directories, got %s", got)
+	}
+	if got := strings.Join(config.Filter.Extensions, ","); got != "go,proto" {
+		t.Errorf("Expected the extensions from the defaults, got %s", got)
+	}
+	
+	for _, defaults := range []map[string]any{
+		{"colour": "red"},
+		{"depth": []any{int64(1), int64(2)}},
+		{"depth": "deep"},
+		{"metric": "words"},
+		{"exclude": []any{[]any{"nested"}}},
+	} {
+		if _, err := ParseArgsWithDefaults([]string{"/tmp"}, defaults); err == nil {
+			t.Errorf("Expected error for %v, got nil", defaults)
+		}
+	}
+}
+
+func TestParseArgsWithDefaults_SkipsOptionsOfOtherCommands(t *testing.T) {
+	defaults := map[string]any{
+		"watch":  true,
+		"format": "html",
+		"o":      "snap.json",
+		"sort":   "change",
+		"from":   "main",
+		"to":     "HEAD",
+	}
+	
+	for _, args := range [][]string{
+		{"/tmp"},
+		{"--format", "json", "/tmp"},
+		{"--rev", "main"},
+		{"history", "/tmp"},
+		{"diff", "old.json", "/tmp"},
+		{"snapshot", "/tmp"},
+	} {
+		if _, err := ParseArgsWithDefaults(args, defaults); err != nil {
+			t.Errorf("Expected the defaults to be skipped for %v, got: %v", args, err)
+		}
+	}
+	
+	config, err := ParseArgsWithDefaults([]string{"/tmp"}, defaults)
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if config.Format != "html" || config.Watch || config.Output != "" || config.From != "" {
+		t.Errorf("Expected only the format to apply, got %+v", config)
+	}
+	
+	config, err = ParseArgsWithDefaults([]string{"snapshot", "/tmp"}, defaults)
+	if err != nil || config.Output != "snap.json" {
+		t.Errorf("Expected -o to apply to snapshot, got %+v (%v)", config, err)
+	}
+	
+	config, err = ParseArgsWithDefaults([]string{"diff", "old.json", "/tmp"}, map[string]any{"to": "v2", "sort": "change:asc"})
+	if err != nil || config.To != "" || config.Sort != (tree.Order{Key: tree.SortChange, Reverse: true}) {
+		t.Errorf("Expected only the sort to apply to a snapshot diff, got %+v (%v)", config, err)
+	}
+	
+	config, err = ParseArgsWithDefaults([]string{"diff", "/tmp"}, defaults)
+	if err != nil || config.From != "main" || config.To != "HEAD" {
+		t.Errorf("Expected the revisions to apply to a diff of one directory, got %+v (%v)", config, err)
+	}
 }
\ No newline at end of file
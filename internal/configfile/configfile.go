@@ -0,0 +1,141 @@
+package configfile
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	
+	"github.com/BurntSushi/toml"
+)
+
+// Config file names: the user's, below the config directory, and the
+// per-repository file
+const (
+	userFile = "loctree/config.toml"
+	repoFile = ".loctree.toml"
+)
+
+// File holds the settings read from config files
+type File struct {
+	Theme    string              `toml:"theme"`    // Built-in theme: dark or light; empty for the default
+	Colors   map[string]string   `toml:"colors"`   // Colours replacing those of the theme, by name
+	Keys     map[string][]string `toml:"keys"`     // Keys bound to actions, by action name
+	Defaults map[string]any      `toml:"defaults"` // Values of command-line options, by flag name
+}
+
+// Load reads the user's config file and the per-repository file for dir,
+// in that order, so the repository's settings win. Missing files are
+// skipped.
+func Load(dir string) (*File, error) {
+	merged := &File{}
+	for _, path := range Paths(dir) {
+		file, err := Read(path)
+		if os.IsNotExist(err) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		merged.merge(file)
+	}
+	return merged, nil
+}
+
+// Paths returns the config files that apply to dir:
+// $XDG_CONFIG_HOME/loctree/config.toml (by default in ~/.config), and the
+// nearest .loctree.toml in dir or a directory above it, up to the root of
+// the repository. dir may also be a file, like a snapshot, whose directory
+// is searched.
+func Paths(dir string) []string {
+	var paths []string
+	if configDir := userConfigDir(); configDir != "" {
+		paths = append(paths, filepath.Join(configDir, filepath.FromSlash(userFile)))
+	}
+	if repo := findRepoFile(dir); repo != "" {
+		paths = append(paths, repo)
+	}
+	return paths
+}
+
+// Read reads a single config file. Keys it does not know are errors, so
+// typos do not go unnoticed.
+func Read(path string) (*File, error) {
+	file := &File{}
+	meta, err := toml.DecodeFile(path, file)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, err
+		}
+		return nil, fmt.Errorf("%s: %v", path, err)
+	}
+	if undecoded := meta.Undecoded(); len(undecoded) > 0 {
+		keys := make([]string, len(undecoded))
+		for i, key := range undecoded {
+			keys[i] = key.String()
+		}
+		return nil, fmt.Errorf("%s: unknown setting %s", path, strings.Join(keys, ", "))
+	}
+	return file, nil
+}
+
+// userConfigDir returns $XDG_CONFIG_HOME, or ~/.config when it is not set
+func userConfigDir() string {
+	if dir := os.Getenv("XDG_CONFIG_HOME"); dir != "" {
+		return dir
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(home, ".config")
+}
+
+// findRepoFile looks for the per-repository file in dir and the directories
+// above it, stopping at the first directory holding .git
+func findRepoFile(dir string) string {
+	dir, err := filepath.Abs(dir)
+	if err != nil {
+		return ""
+	}
+	for {
+		path := filepath.Join(dir, repoFile)
+		if info, err := os.Stat(path); err == nil && !info.IsDir() {
+			return path
+		}
+		if _, err := os.Stat(filepath.Join(dir, ".git")); err == nil {
+			return ""
+		}
+		
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return ""
+		}
+		dir = parent
+	}
+}
+
+// merge adds the settings of other, which replace those already set
+func (f *File) merge(other *File) {
+	if other.Theme != "" {
+		f.Theme = other.Theme
+	}
+	f.Colors = mergeMaps(f.Colors, other.Colors)
+	f.Keys = mergeMaps(f.Keys, other.Keys)
+	f.Defaults = mergeMaps(f.Defaults, other.Defaults)
+}
+
+// mergeMaps returns dst with the entries of src added, allocating it when
+// needed
+func mergeMaps[V any](dst, src map[string]V) map[string]V {
+	if len(src) == 0 {
+		return dst
+	}
+	if dst == nil {
+		dst = make(map[string]V, len(src))
+	}
+	for key, value := range src {
+		dst[key] = value
+	}
+	return dst
+}
\ No newline at end of file
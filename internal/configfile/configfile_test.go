@@ -0,0 +1,112 @@
+package configfile
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	
+	"github.com/user/loctree/internal/testutil"
+)
+
+func TestLoad_RepoFileOverridesUserFile(t *testing.T) {
+	configHome := t.TempDir()
+	t.Setenv("XDG_CONFIG_HOME", configHome)
+	testutil.WriteFile(t, filepath.Join(configHome, "loctree", "config.toml"), `
+theme = "light"
+
+[colors]
+count = "208"
+
+[keys]
+quit = ["q", "Q"]
+
+[defaults]
+metric = "code"
+depth = 2
+`)
+
+	repo := t.TempDir()
+	testutil.WriteFile(t, filepath.Join(repo, ".loctree.toml"), `
+theme = "dark"
+
+[defaults]
+depth = 3
+exclude-dir = ["vendor", "testdata"]
+`)
+	dir := filepath.Join(repo, "internal", "ui")
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	
+	file, err := Load(dir)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if file.Theme != "dark" {
+		t.Errorf("Expected the repository's theme, got %q", file.Theme)
+	}
+	if file.Colors["count"] != "208" || strings.Join(file.Keys["quit"], " ") != "q Q" {
+		t.Errorf("Expected the user's colours and keys, got %v and %v", file.Colors, file.Keys)
+	}
+	if file.Defaults["metric"] != "code" || file.Defaults["depth"] != int64(3) {
+		t.Errorf("Expected the user's metric and the repository's depth, got %v", file.Defaults)
+	}
+	if dirs, ok := file.Defaults["exclude-dir"].([]any); !ok || len(dirs) != 2 {
+		t.Errorf("Expected two excluded directories, got %v", file.Defaults["exclude-dir"])
+	}
+}
+
+func TestLoad_NoFiles(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	
+	file, err := Load(t.TempDir())
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if file.Theme != "" || len(file.Defaults) != 0 {
+		t.Errorf("Expected no settings, got %+v", file)
+	}
+}
+
+func TestPaths_StopsAtRepositoryRoot(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", "/config")
+	outer := t.TempDir()
+	testutil.WriteFile(t, filepath.Join(outer, ".loctree.toml"), "")
+	repo := filepath.Join(outer, "repo")
+	if err := os.MkdirAll(filepath.Join(repo, ".git"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	
+	paths := Paths(repo)
+	if len(paths) != 1 || paths[0] != filepath.Join("/config", "loctree", "config.toml") {
+		t.Errorf("Expected only the user's config file, got %v", paths)
+	}
+	
+	testutil.WriteFile(t, filepath.Join(repo, ".loctree.toml"), "")
+	if paths := Paths(repo); len(paths) != 2 || paths[1] != filepath.Join(repo, ".loctree.toml") {
+		t.Errorf("Expected the repository's file, got %v", paths)
+	}
+	if paths := Paths(filepath.Join(repo, "snap.json")); len(paths) != 2 || paths[1] != filepath.Join(repo, ".loctree.toml") {
+		t.Errorf("Expected the repository's file for a file in it, got %v", paths)
+	}
+}
+
+func TestRead_UnknownSetting(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.toml")
+	testutil.WriteFile(t, path, "theme = \"dark\"\ncolour = \"red\"\n")
+	
+	_, err := Read(path)
+	if err == nil || !strings.Contains(err.Error(), "unknown setting colour") {
+		t.Errorf("Expected an error naming the unknown setting, got %v", err)
+	}
+}
+
+func TestRead_Invalid(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.toml")
+	testutil.WriteFile(t, path, "theme = \n")
+	
+	if _, err := Read(path); err == nil || !strings.Contains(err.Error(), path) {
+		t.Errorf("Expected an error naming the file, got %v", err)
+	}
+}
\ No newline at end of file
@@ -0,0 +1,204 @@
+package filter
+
+import (
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	
+	"github.com/user/loctree/internal/ignore"
+)
+
+// Filter decides which files and directories a scan visits. The zero value
+// skips hidden entries and respects ignore files, and nothing else.
+type Filter struct {
+	Include     []string // Glob patterns; when set, only matching files are counted
+	Exclude     []string // Glob patterns for files or directories to skip
+	Extensions  []string // File extensions to count (e.g. "go" or ".proto"); empty means all
+	ExcludeDirs []string // Directory names or relative paths to skip
+	NoIgnore    bool     // Disable .gitignore, .ignore and .loctreeignore rules
+}
+
+// String describes the rules as the command-line options that set them,
+// e.g. "--ext go,proto --exclude-dir testdata", or "" for the defaults
+func (f *Filter) String() string {
+	if f == nil {
+		return ""
+	}
+	
+	var opts []string
+	for _, pattern := range f.Include {
+		opts = append(opts, "--include "+pattern)
+	}
+	for _, pattern := range f.Exclude {
+		opts = append(opts, "--exclude "+pattern)
+	}
+	if len(f.Extensions) > 0 {
+		opts = append(opts, "--ext "+strings.Join(f.Extensions, ","))
+	}
+	for _, dir := range f.ExcludeDirs {
+		opts = append(opts, "--exclude-dir "+dir)
+	}
+	if f.NoIgnore {
+		opts = append(opts, "--no-ignore")
+	}
+	return strings.Join(opts, " ")
+}
+
+// Walk applies a Filter to a single directory walk
+type Walk struct {
+	root       string          // Directory on disk; empty when paths are relative to fsys
+	fsys       fs.FS           // Source of ignore files
+	ignores    *ignore.Matcher // Rules from ignore files, nil when disabled
+	excludes   *ignore.Matcher // Rules from Exclude and ExcludeDirs
+	includes   *ignore.Matcher // Rules from Include, nil when every file is included
+	extensions map[string]bool // Lower-case extensions with a leading dot
+	loaded     map[string]bool // Directories whose ignore files have been read
+}
+
+// Begin starts a walk of a directory on disk, rooted at root. Allow takes
+// paths as returned by filepath.WalkDir. A nil Filter uses the defaults.
+func (f *Filter) Begin(root string) *Walk {
+	w := f.BeginFS(os.DirFS(root))
+	w.root = root
+	return w
+}
+
+// BeginFS starts a walk of fsys. Allow takes slash-separated paths relative
+// to the root of fsys, as returned by fs.WalkDir. A nil Filter uses the defaults.
+func (f *Filter) BeginFS(fsys fs.FS) *Walk {
+	if f == nil {
+		f = &Filter{}
+	}
+	
+	w := &Walk{
+		fsys:     fsys,
+		excludes: ignore.New(),
+	}
+	
+	if !f.NoIgnore {
+		w.ignores = ignore.New()
+		w.ignores.LoadFS(fsys, "")
+		w.loaded = map[string]bool{".": true}
+	}
+	
+	// Command-line globs share gitignore syntax, anchored at the root
+	for _, pattern := range f.Exclude {
+		w.excludes.AddPattern("", pattern)
+	}
+	for _, dir := range f.ExcludeDirs {
+		w.excludes.AddPattern("", strings.TrimRight(dir, "/")+"/")
+	}
+	if len(f.Include) > 0 {
+		w.includes = ignore.New()
+		for _, pattern := range f.Include {
+			w.includes.AddPattern("", pattern)
+		}
+	}
+	
+	if len(f.Extensions) > 0 {
+		w.extensions = make(map[string]bool)
+		for _, ext := range f.Extensions {
+			ext = strings.ToLower(strings.TrimSpace(ext))
+			if ext == "" {
+				continue
+			}
+			if !strings.HasPrefix(ext, ".") {
+				ext = "." + ext
+			}
+			w.extensions[ext] = true
+		}
+	}
+	
+	return w
+}
+
+// Allow reports whether the walk should visit path. Allowed directories have
+// their ignore files loaded so the rules apply to their contents.
+func (w *Walk) Allow(filePath string, isDir bool) bool {
+	relPath, err := w.relative(filePath)
+	if err != nil || relPath == "." {
+		return true // The root is always visited
+	}
+	
+	// Skip hidden files and directories
+	name := path.Base(relPath)
+	if strings.HasPrefix(name, ".") {
+		return false
+	}
+	
+	if w.ignores != nil && w.ignores.Match(relPath, isDir) {
+		return false
+	}
+	if w.excludes.Match(relPath, isDir) {
+		return false
+	}
+	
+	if isDir {
+		if w.ignores != nil && !w.loaded[relPath] {
+			w.ignores.LoadFS(w.fsys, relPath)
+			w.loaded[relPath] = true
+		}
+		return true
+	}
+	
+	if w.extensions != nil && !w.extensions[strings.ToLower(filepath.Ext(name))] {
+		return false
+	}
+	if w.includes != nil && !w.includes.Match(relPath, false) {
+		return false
+	}
+	
+	return true
+}
+
+// AllowPath is Allow for a path whose parent directories the walk has not
+// visited yet: they are checked, and their ignore files loaded, on the way
+// down, so a fresh walk gives the answer a full walk would
+func (w *Walk) AllowPath(filePath string, isDir bool) bool {
+	relPath, err := w.relative(filePath)
+	if err != nil || relPath == "." {
+		return true
+	}
+	
+	parts := strings.Split(relPath, "/")
+	for i := 1; i < len(parts); i++ {
+		dir := path.Join(parts[:i]...)
+		if w.root != "" {
+			dir = filepath.Join(w.root, filepath.FromSlash(dir))
+		}
+		if !w.Allow(dir, true) {
+			return false
+		}
+	}
+	return w.Allow(filePath, isDir)
+}
+
+// Reload forgets the ignore files read for dir and the directories below
+// it, as when they changed. Those of dir are read again right away, the
+// others when the walk next visits them.
+func (w *Walk) Reload(dirPath string) {
+	relPath, err := w.relative(dirPath)
+	if err != nil || w.ignores == nil {
+		return
+	}
+	for dir := range w.loaded {
+		if relPath == "." || dir == relPath || strings.HasPrefix(dir, relPath+"/") {
+			w.ignores.Forget(dir)
+			delete(w.loaded, dir)
+		}
+	}
+	w.ignores.LoadFS(w.fsys, relPath)
+	w.loaded[relPath] = true
+}
+
+// relative converts a path passed to Allow into a slash-separated path
+// relative to the walk root
+func (w *Walk) relative(filePath string) (string, error) {
+	if w.root == "" {
+		return filePath, nil
+	}
+	relPath, err := filepath.Rel(w.root, filePath)
+	return filepath.ToSlash(relPath), err
+}
\ No newline at end of file
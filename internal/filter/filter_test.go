@@ -0,0 +1,181 @@
+package filter
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+type allowCase struct {
+	path     string
+	isDir    bool
+	expected bool
+}
+
+func assertAllowed(t *testing.T, w *Walk, root string, cases []allowCase) {
+	t.Helper()
+	for _, c := range cases {
+		path := filepath.Join(root, filepath.FromSlash(c.path))
+		if got := w.Allow(path, c.isDir); got != c.expected {
+			t.Errorf("Allow(%q, isDir=%v): expected %v, got %v", c.path, c.isDir, c.expected, got)
+		}
+	}
+}
+
+func TestAllow_NilFilterSkipsHiddenEntries(t *testing.T) {
+	root := t.TempDir()
+	var f *Filter
+	w := f.Begin(root)
+	
+	assertAllowed(t, w, root, []allowCase{
+		{".", true, true},
+		{"src", true, true},
+		{"src/main.go", false, true},
+		{".git", true, false},
+		{"src/.env", false, false},
+	})
+}
+
+func TestAllow_Extensions(t *testing.T) {
+	root := t.TempDir()
+	w := (&Filter{Extensions: []string{"go", ".PROTO"}}).Begin(root)
+	
+	assertAllowed(t, w, root, []allowCase{
+		{"main.go", false, true},
+		{"api/service.proto", false, true},
+		{"README.md", false, false},
+		{"docs", true, true},
+	})
+}
+
+func TestAllow_IncludeOnlyAppliesToFiles(t *testing.T) {
+	root := t.TempDir()
+	w := (&Filter{Include: []string{"*.go", "proto/**"}}).Begin(root)
+	
+	assertAllowed(t, w, root, []allowCase{
+		{"internal", true, true},
+		{"internal/main.go", false, true},
+		{"proto/v1/api.proto", false, true},
+		{"internal/notes.txt", false, false},
+	})
+}
+
+func TestAllow_ExcludeAndExcludeDirs(t *testing.T) {
+	root := t.TempDir()
+	w := (&Filter{
+		Exclude:     []string{"*_test.go"},
+		ExcludeDirs: []string{"testdata", "internal/gen/"},
+	}).Begin(root)
+	
+	assertAllowed(t, w, root, []allowCase{
+		{"main.go", false, true},
+		{"main_test.go", false, false},
+		{"testdata", true, false},
+		{"pkg/testdata", true, false},
+		{"internal/gen", true, false},
+		{"gen", true, true},
+		{"testdata", false, true},
+	})
+}
+
+func TestAllow_IgnoreFiles(t *testing.T) {
+	root := t.TempDir()
+	if err := os.WriteFile(filepath.Join(root, ".gitignore"), []byte("dist/\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	
+	w := (&Filter{}).Begin(root)
+	assertAllowed(t, w, root, []allowCase{{"dist", true, false}})
+	
+	w = (&Filter{NoIgnore: true}).Begin(root)
+	assertAllowed(t, w, root, []allowCase{{"dist", true, true}})
+}
+
+func TestAllow_LoadsNestedIgnoreFilesOnEntry(t *testing.T) {
+	root := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(root, "web"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(root, "web", ".ignore"), []byte("*.min.js\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	
+	w := (&Filter{}).Begin(root)
+	assertAllowed(t, w, root, []allowCase{
+		{"web", true, true},
+		{"web/app.min.js", false, false},
+		{"web/app.js", false, true},
+	})
+}
+
+func TestAllowPath_ChecksParentDirectories(t *testing.T) {
+	root := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(root, "web", "src"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(root, "web", ".ignore"), []byte("*.min.js\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	
+	for _, c := range []allowCase{
+		{"web/src/app.min.js", false, false},
+		{"web/src/app.js", false, true},
+		{"node_modules/lib/index.js", false, false},
+		{".git/config", false, false},
+	} {
+		w := (&Filter{ExcludeDirs: []string{"node_modules"}}).Begin(root)
+		if got := w.AllowPath(filepath.Join(root, filepath.FromSlash(c.path)), c.isDir); got != c.expected {
+			t.Errorf("AllowPath(%q): expected %v, got %v", c.path, c.expected, got)
+		}
+	}
+}
+
+func TestReload_RereadsChangedIgnoreFiles(t *testing.T) {
+	root := t.TempDir()
+	ignoreFile := filepath.Join(root, "web", ".ignore")
+	if err := os.MkdirAll(filepath.Join(root, "web", "src"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(ignoreFile, []byte("*.min.js\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	
+	w := (&Filter{}).Begin(root)
+	app := filepath.Join(root, "web", "src", "app.min.js")
+	if w.AllowPath(app, false) {
+		t.Fatal("Expected app.min.js to be ignored")
+	}
+	
+	// Ignore files are read once per walk, until reloaded
+	if err := os.WriteFile(ignoreFile, []byte("*.map\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if w.AllowPath(app, false) {
+		t.Error("Expected the rules read before to apply until reloaded")
+	}
+	w.Reload(filepath.Join(root, "web"))
+	if !w.AllowPath(app, false) {
+		t.Error("Expected app.min.js to be allowed after reloading")
+	}
+	if w.AllowPath(filepath.Join(root, "web", "src", "app.js.map"), false) {
+		t.Error("Expected the new rule to apply after reloading")
+	}
+}
+
+func TestString(t *testing.T) {
+	var none *Filter
+	if got := none.String(); got != "" {
+		t.Errorf("Expected no rules for a nil filter, got %q", got)
+	}
+	
+	f := &Filter{
+		Include:     []string{"*.go"},
+		Extensions:  []string{"go", "proto"},
+		ExcludeDirs: []string{"testdata"},
+		NoIgnore:    true,
+	}
+	expected := "--include *.go --ext go,proto --exclude-dir testdata --no-ignore"
+	if got := f.String(); got != expected {
+		t.Errorf("Expected %q, got %q", expected, got)
+	}
+}
\ No newline at end of file
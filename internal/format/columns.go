@@ -0,0 +1,145 @@
+package format
+
+import (
+	"fmt"
+	"strings"
+	
+	"github.com/user/loctree/internal/tree"
+)
+
+// Columns is a set of optional columns shown next to each tree row
+type Columns uint8
+
+const (
+	ColumnParent Columns = 1 << iota // Share of the parent's count
+	ColumnRoot                       // Share of the root's count
+	ColumnFiles                      // Number of files
+	ColumnBar                        // Bar proportional to the share of the parent
+)
+
+// allColumns lists every column in display order, named by columnNames
+var (
+	allColumns  = []Columns{ColumnParent, ColumnRoot, ColumnFiles, ColumnBar}
+	columnNames = []string{"parent", "root", "files", "bar"}
+)
+
+// Has reports whether every column in col is in the set
+func (c Columns) Has(col Columns) bool {
+	return c&col == col
+}
+
+// Toggle adds col to the set, or removes it when it is already there
+func (c Columns) Toggle(col Columns) Columns {
+	return c ^ col
+}
+
+// List returns the columns in the set, each on its own, in display order
+func (c Columns) List() []Columns {
+	var list []Columns
+	for _, col := range allColumns {
+		if c.Has(col) {
+			list = append(list, col)
+		}
+	}
+	return list
+}
+
+// String returns the columns as accepted by ParseColumns, e.g. "parent,bar"
+func (c Columns) String() string {
+	var names []string
+	for i, col := range allColumns {
+		if c.Has(col) {
+			names = append(names, columnNames[i])
+		}
+	}
+	return strings.Join(names, ",")
+}
+
+// ParseColumns converts a comma-separated list of column names into a set
+func ParseColumns(value string) (Columns, error) {
+	var c Columns
+	for _, name := range strings.Split(value, ",") {
+		name = strings.TrimSpace(name)
+		if name == "" {
+			continue
+		}
+		
+		found := false
+		for i, columnName := range columnNames {
+			if name == columnName {
+				c |= allColumns[i]
+				found = true
+			}
+		}
+		if !found {
+			return 0, fmt.Errorf("unknown column %q (expected %s)", name, strings.Join(columnNames, ", "))
+		}
+	}
+	return c, nil
+}
+
+// Cell formats one column for a node. Shares are right-aligned to a fixed
+// width, file counts to the width of the root's count and bars are padded
+// to barWidth, so cells of the same column line up.
+func Cell(col Columns, node *tree.DirectoryNode, metric tree.Metric, barWidth int) string {
+	root := node
+	for root.Parent != nil {
+		root = root.Parent
+	}
+	
+	switch col {
+	case ColumnParent:
+		return Percent(node.Count(metric), parentCount(node, metric))
+	case ColumnRoot:
+		return Percent(node.Count(metric), root.Count(metric))
+	case ColumnFiles:
+		return fmt.Sprintf("%*s", len(Number(root.FileCount)), Number(node.FileCount))
+	case ColumnBar:
+		return Bar(share(node.Count(metric), parentCount(node, metric)), barWidth)
+	default:
+		return ""
+	}
+}
+
+// parentCount returns the count of a node's parent, or of the node itself
+// for the root
+func parentCount(node *tree.DirectoryNode, metric tree.Metric) int {
+	if node.Parent == nil {
+		return node.Count(metric)
+	}
+	return node.Parent.Count(metric)
+}
+
+// share returns part as a fraction of whole, zero when whole is empty
+func share(part, whole int) float64 {
+	if whole <= 0 {
+		return 0
+	}
+	return float64(part) / float64(whole)
+}
+
+// Percent formats part as a percentage of whole, right-aligned to six
+// characters (e.g. " 12.5%")
+func Percent(part, whole int) string {
+	return fmt.Sprintf("%5.1f%%", share(part, whole)*100)
+}
+
+// barBlocks are the partial bar widths in eighths used by Bar, narrowest first
+var barBlocks = []rune("▏▎▍▌▋▊▉")
+
+// Bar draws a horizontal bar filling fraction of width characters, in
+// eighths of a character, padded with spaces to width (e.g. "███▌  ")
+func Bar(fraction float64, width int) string {
+	fraction = min(max(fraction, 0), 1)
+	eighths := int(fraction*float64(width*8) + 0.5)
+	
+	var b strings.Builder
+	b.WriteString(strings.Repeat("█", eighths/8))
+	cells := eighths / 8
+	if eighths%8 > 0 {
+		b.WriteRune(barBlocks[eighths%8-1])
+		cells++
+	}
+	b.WriteString(strings.Repeat(" ", width-cells))
+	return b.String()
+}
\ No newline at end of file
@@ -0,0 +1,107 @@
+package format
+
+import (
+	"testing"
+	
+	"github.com/user/loctree/internal/tree"
+)
+
+func TestParseColumns(t *testing.T) {
+	cols, err := ParseColumns("bar, parent")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if cols != ColumnParent|ColumnBar {
+		t.Errorf("Expected parent and bar, got %s", cols)
+	}
+	if cols.String() != "parent,bar" {
+		t.Errorf("Expected 'parent,bar', got '%s'", cols.String())
+	}
+	if list := cols.List(); len(list) != 2 || list[0] != ColumnParent || list[1] != ColumnBar {
+		t.Errorf("Expected parent then bar, got %v", list)
+	}
+	
+	if cols, err := ParseColumns(""); err != nil || cols != 0 {
+		t.Errorf("Expected no columns, got %s (%v)", cols, err)
+	}
+	if _, err := ParseColumns("parent,size"); err == nil {
+		t.Error("Expected an error for an unknown column")
+	}
+}
+
+func TestColumns_Toggle(t *testing.T) {
+	cols := ColumnRoot.Toggle(ColumnFiles)
+	if !cols.Has(ColumnRoot) || !cols.Has(ColumnFiles) {
+		t.Errorf("Expected root and files, got %s", cols)
+	}
+	if cols = cols.Toggle(ColumnRoot); cols != ColumnFiles {
+		t.Errorf("Expected only files, got %s", cols)
+	}
+}
+
+func TestPercent(t *testing.T) {
+	cases := []struct {
+		part, whole int
+		expected    string
+	}{
+		{1, 8, " 12.5%"},
+		{5, 5, "100.0%"},
+		{0, 0, "  0.0%"},
+	}
+	
+	for _, c := range cases {
+		if got := Percent(c.part, c.whole); got != c.expected {
+			t.Errorf("Percent(%d, %d): expected '%s', got '%s'", c.part, c.whole, c.expected, got)
+		}
+	}
+}
+
+func TestBar(t *testing.T) {
+	cases := []struct {
+		fraction float64
+		width    int
+		expected string
+	}{
+		{0, 4, "    "},
+		{1, 4, "████"},
+		{0.5, 4, "██  "},
+		{0.4375, 4, "█▊  "},
+		{2, 3, "███"},
+	}
+	
+	for _, c := range cases {
+		if got := Bar(c.fraction, c.width); got != c.expected {
+			t.Errorf("Bar(%v, %d): expected '%s', got '%s'", c.fraction, c.width, c.expected, got)
+		}
+	}
+}
+
+func TestCell(t *testing.T) {
+	root := tree.NewDirectoryNode("root", "/root")
+	child := tree.NewDirectoryNode("child", "/root/child")
+	grandchild := tree.NewDirectoryNode("grandchild", "/root/child/grandchild")
+	root.AddChild(child)
+	child.AddChild(grandchild)
+	root.LOC, root.FileCount = 400, 1200
+	child.LOC, child.FileCount = 200, 30
+	grandchild.LOC, grandchild.FileCount = 50, 3
+	
+	cases := []struct {
+		col      Columns
+		node     *tree.DirectoryNode
+		expected string
+	}{
+		{ColumnParent, grandchild, " 25.0%"},
+		{ColumnRoot, grandchild, " 12.5%"},
+		{ColumnParent, root, "100.0%"},
+		{ColumnFiles, grandchild, "    3"},
+		{ColumnFiles, root, "1,200"},
+		{ColumnBar, child, "██  "},
+	}
+	
+	for _, c := range cases {
+		if got := Cell(c.col, c.node, tree.MetricLines, 4); got != c.expected {
+			t.Errorf("Cell(%s, %s): expected '%s', got '%s'", c.col, c.node.Name, c.expected, got)
+		}
+	}
+}
\ No newline at end of file
@@ -0,0 +1,135 @@
+package format
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	
+	"github.com/user/loctree/internal/tree"
+)
+
+// Number formats an integer with thousands separators (e.g. 12,340)
+func Number(n int) string {
+	digits := strconv.Itoa(n)
+	sign := ""
+	if n < 0 {
+		sign = "-"
+		digits = digits[1:]
+	}
+	
+	var b strings.Builder
+	for i, d := range digits {
+		if i > 0 && (len(digits)-i)%3 == 0 {
+			b.WriteByte(',')
+		}
+		b.WriteRune(d)
+	}
+	
+	return sign + b.String()
+}
+
+// Bytes formats a size in bytes using binary units (e.g. 512 B, 1.5 KB, 12.0 MB)
+func Bytes(size int64) string {
+	const unit = 1024
+	if size < unit {
+		return fmt.Sprintf("%d B", size)
+	}
+	
+	value := float64(size)
+	units := []string{"KB", "MB", "GB", "TB"}
+	i := -1
+	for value >= unit && i < len(units)-1 {
+		value /= unit
+		i++
+	}
+	
+	return fmt.Sprintf("%.1f %s", value, units[i])
+}
+
+// Languages formats a language breakdown as "Go 12,340 · YAML 800 · Shell 95".
+// At most limit languages are listed; the rest are summarised as "+N more".
+// A limit of zero or less lists every language.
+func Languages(breakdown []tree.LanguageLOC, limit int) string {
+	shown := breakdown
+	if limit > 0 && len(breakdown) > limit {
+		shown = breakdown[:limit]
+	}
+	
+	parts := make([]string, 0, len(shown)+1)
+	for _, entry := range shown {
+		parts = append(parts, fmt.Sprintf("%s %s", entry.Language, Number(entry.LOC)))
+	}
+	if len(shown) < len(breakdown) {
+		parts = append(parts, fmt.Sprintf("+%d more", len(breakdown)-len(shown)))
+	}
+	
+	return strings.Join(parts, " · ")
+}
+
+// TreeRow formats a node as a plain tree row: indentation, expand indicator,
+// count for the metric and name, plus language and size for files
+func TreeRow(node *tree.DirectoryNode, depth int, metric tree.Metric, showFiles bool) string {
+	line := fmt.Sprintf("%s%d %s", TreePrefix(node, depth, showFiles), node.Count(metric), node.Name)
+	if node.IsFile {
+		line += " " + FileDetails(node)
+	}
+	
+	return line
+}
+
+// TreePrefix formats the indentation and expand indicator of a tree row
+func TreePrefix(node *tree.DirectoryNode, depth int, showFiles bool) string {
+	indent := strings.Repeat("  ", depth)
+	
+	// Determine indicator
+	indicator := ""
+	if node.HasVisibleChildren(showFiles) {
+		if node.IsExpanded {
+			indicator = "▼ "
+		} else {
+			indicator = "▶ "
+		}
+	}
+	
+	return indent + indicator
+}
+
+// Change formats the lines added and removed since an earlier scan, e.g. "+1,203 / -88"
+func Change(change *tree.Change) string {
+	return fmt.Sprintf("+%s / -%s", Number(change.Added), Number(change.Removed))
+}
+
+// FileDetails formats the language and size of a file, e.g. "(Go, 1.2 KB)"
+func FileDetails(node *tree.DirectoryNode) string {
+	if node.Language == "" {
+		return fmt.Sprintf("(%s)", Bytes(node.Size))
+	}
+	return fmt.Sprintf("(%s, %s)", node.Language, Bytes(node.Size))
+}
+
+// sparkBlocks are the bar heights used by Sparkline, lowest first
+var sparkBlocks = []rune("▁▂▃▄▅▆▇█")
+
+// Sparkline draws values as a row of bars scaled between their minimum and
+// maximum, e.g. "▁▂▄▇█"
+func Sparkline(values []int) string {
+	if len(values) == 0 {
+		return ""
+	}
+	
+	low, high := values[0], values[0]
+	for _, v := range values {
+		low = min(low, v)
+		high = max(high, v)
+	}
+	
+	var b strings.Builder
+	for _, v := range values {
+		level := 0
+		if high > low {
+			level = (v - low) * (len(sparkBlocks) - 1) / (high - low)
+		}
+		b.WriteRune(sparkBlocks[level])
+	}
+	return b.String()
+}
\ No newline at end of file
@@ -0,0 +1,117 @@
+package format
+
+import (
+	"testing"
+	
+	"github.com/user/loctree/internal/tree"
+)
+
+func TestNumber(t *testing.T) {
+	cases := map[int]string{
+		0:       "0",
+		95:      "95",
+		800:     "800",
+		12340:   "12,340",
+		1234567: "1,234,567",
+		-1203:   "-1,203",
+		-100:    "-100",
+		1000000: "1,000,000",
+	}
+	
+	for n, expected := range cases {
+		if got := Number(n); got != expected {
+			t.Errorf("Number(%d): expected '%s', got '%s'", n, expected, got)
+		}
+	}
+}
+
+func TestBytes(t *testing.T) {
+	cases := map[int64]string{
+		0:                "0 B",
+		512:              "512 B",
+		1024:             "1.0 KB",
+		1536:             "1.5 KB",
+		12 * 1024 * 1024: "12.0 MB",
+		3 << 30:          "3.0 GB",
+	}
+	
+	for size, expected := range cases {
+		if got := Bytes(size); got != expected {
+			t.Errorf("Bytes(%d): expected '%s', got '%s'", size, expected, got)
+		}
+	}
+}
+
+func TestLanguages(t *testing.T) {
+	breakdown := []tree.LanguageLOC{
+		{Language: "Go", LOC: 12340},
+		{Language: "YAML", LOC: 800},
+		{Language: "Shell", LOC: 95},
+	}
+	
+	result := Languages(breakdown, 0)
+	expected := "Go 12,340 · YAML 800 · Shell 95"
+	if result != expected {
+		t.Errorf("Expected '%s', got '%s'", expected, result)
+	}
+}
+
+func TestLanguages_Limit(t *testing.T) {
+	breakdown := []tree.LanguageLOC{
+		{Language: "Go", LOC: 300},
+		{Language: "YAML", LOC: 200},
+		{Language: "Shell", LOC: 100},
+	}
+	
+	result := Languages(breakdown, 1)
+	expected := "Go 300 · +2 more"
+	if result != expected {
+		t.Errorf("Expected '%s', got '%s'", expected, result)
+	}
+}
+
+func TestLanguages_Empty(t *testing.T) {
+	if result := Languages(nil, 0); result != "" {
+		t.Errorf("Expected empty string, got '%s'", result)
+	}
+}
+
+func TestTreeRow(t *testing.T) {
+	root := tree.NewDirectoryNode("root", "/root")
+	file := tree.NewFileNode("main.go", "/root/main.go")
+	file.FileLOC = 1200
+	file.Language = "Go"
+	file.Size = 2048
+	root.AddFile(file)
+	root.FileLOC = 1200
+	root.CalculateLOC()
+	
+	if row := TreeRow(root, 0, tree.MetricLines, false); row != "1200 root" {
+		t.Errorf("Expected '1200 root', got '%s'", row)
+	}
+	if row := TreeRow(root, 0, tree.MetricLines, true); row != "▶ 1200 root" {
+		t.Errorf("Expected '▶ 1200 root', got '%s'", row)
+	}
+	if row := TreeRow(file, 1, tree.MetricLines, true); row != "  1200 main.go (Go, 2.0 KB)" {
+		t.Errorf("Expected '  1200 main.go (Go, 2.0 KB)', got '%s'", row)
+	}
+}
+
+func TestChange(t *testing.T) {
+	change := &tree.Change{Added: 1203, Removed: 88}
+	if got := Change(change); got != "+1,203 / -88" {
+		t.Errorf("Expected '+1,203 / -88', got '%s'", got)
+	}
+}
+
+func TestSparkline(t *testing.T) {
+	if got := Sparkline([]int{0, 7, 14}); got != "▁▄█" {
+		t.Errorf("Expected '▁▄█', got '%s'", got)
+	}
+	if got := Sparkline([]int{5, 5}); got != "▁▁" {
+		t.Errorf("Expected a flat line for equal values, got '%s'", got)
+	}
+	if got := Sparkline(nil); got != "" {
+		t.Errorf("Expected empty sparkline, got '%s'", got)
+	}
+}
\ No newline at end of file
@@ -0,0 +1,328 @@
+package gitfs
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os/exec"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// FS is a read-only view of a directory as it was at a git revision. The
+// tree is listed once with git ls-tree; file contents are read on demand
+// through a single git cat-file --batch process, so the working copy is
+// never touched. FS is safe for concurrent use.
+type FS struct {
+	dir     string            // Directory git runs in
+	entries map[string]*entry // Entries by slash-separated path, "." for the root
+	
+	mu     sync.Mutex // Guards the cat-file process
+	batch  *exec.Cmd
+	stdin  io.WriteCloser
+	stdout *bufio.Reader
+}
+
+// entry is a file, symbolic link or directory in the tree
+type entry struct {
+	name     string
+	mode     fs.FileMode
+	hash     string
+	size     int64
+	children []*entry // Directory contents, sorted by name
+}
+
+// ErrNotExist is wrapped by the error Open returns when the directory did
+// not exist at the revision
+var ErrNotExist = errors.New("does not exist at revision")
+
+// Open lists the tree of rev for dir, which may be the top of a repository
+// or any directory inside one; paths in the FS are relative to dir
+func Open(dir, rev string) (*FS, error) {
+	commit, err := Git(dir, "rev-parse", "--verify", "--quiet", rev+"^{commit}")
+	if err != nil {
+		return nil, fmt.Errorf("unknown revision %q in %s", rev, dir)
+	}
+	prefix, err := Git(dir, "rev-parse", "--show-prefix")
+	if err != nil {
+		return nil, err
+	}
+	
+	// Restrict the listing to dir, which is missing if it did not exist at rev
+	treeish := commit
+	if prefix = strings.TrimSuffix(prefix, "/"); prefix != "" {
+		treeish += ":" + prefix
+	}
+	listing, err := Git(dir, "ls-tree", "--full-tree", "-r", "-t", "-l", "-z", treeish)
+	if err != nil {
+		if _, missing := Git(dir, "cat-file", "-e", treeish); missing != nil && prefix != "" {
+			return nil, fmt.Errorf("%s %w %q", dir, ErrNotExist, rev)
+		}
+		return nil, err
+	}
+	
+	f := &FS{
+		dir:     dir,
+		entries: map[string]*entry{".": {name: ".", mode: fs.ModeDir | 0o755}},
+	}
+	if err := f.parseListing(listing); err != nil {
+		return nil, err
+	}
+	return f, nil
+}
+
+// parseListing adds the entries printed by ls-tree -r -t -l -z. Trees are
+// listed before their contents.
+func (f *FS) parseListing(listing string) error {
+	for _, record := range strings.Split(listing, "\x00") {
+		if record == "" {
+			continue
+		}
+		
+		// <mode> SP <type> SP <object> SP <size> TAB <path>
+		meta, name, ok := strings.Cut(record, "\t")
+		fields := strings.Fields(meta)
+		if !ok || len(fields) != 4 {
+			return fmt.Errorf("unexpected ls-tree output: %q", record)
+		}
+		
+		mode, ok := fileMode(fields[0])
+		if !ok {
+			continue // Submodules have no contents in this repository
+		}
+		size, _ := strconv.ParseInt(fields[3], 10, 64) // "-" for trees
+		
+		parent := f.entries[path.Dir(name)]
+		if parent == nil {
+			return fmt.Errorf("ls-tree listed %q before its directory", name)
+		}
+		e := &entry{name: path.Base(name), mode: mode, hash: fields[2], size: size}
+		parent.children = append(parent.children, e)
+		f.entries[name] = e
+	}
+	
+	// git orders directories as if their names ended in "/"; fs.FS wants plain names
+	for _, e := range f.entries {
+		sort.Slice(e.children, func(i, j int) bool {
+			return e.children[i].name < e.children[j].name
+		})
+	}
+	return nil
+}
+
+// fileMode converts a git tree entry mode
+func fileMode(gitMode string) (fs.FileMode, bool) {
+	switch gitMode {
+	case "040000":
+		return fs.ModeDir | 0o755, true
+	case "100755":
+		return 0o755, true
+	case "100644":
+		return 0o644, true
+	case "120000":
+		return fs.ModeSymlink | 0o777, true
+	default:
+		return 0, false
+	}
+}
+
+// Open opens the named file or directory (fs.FS)
+func (f *FS) Open(name string) (fs.File, error) {
+	e, err := f.lookup("open", name)
+	if err != nil {
+		return nil, err
+	}
+	if e.mode.IsDir() {
+		return &dir{info: info{e}}, nil
+	}
+	
+	data, err := f.readBlob(e.hash)
+	if err != nil {
+		return nil, &fs.PathError{Op: "open", Path: name, Err: err}
+	}
+	return &file{info: info{e}, Reader: bytes.NewReader(data)}, nil
+}
+
+// ReadDir lists a directory without reading any file contents (fs.ReadDirFS)
+func (f *FS) ReadDir(name string) ([]fs.DirEntry, error) {
+	e, err := f.lookup("readdir", name)
+	if err != nil {
+		return nil, err
+	}
+	if !e.mode.IsDir() {
+		return nil, &fs.PathError{Op: "readdir", Path: name, Err: errors.New("not a directory")}
+	}
+	return dirEntries(e.children), nil
+}
+
+// Stat describes the named file or directory (fs.StatFS)
+func (f *FS) Stat(name string) (fs.FileInfo, error) {
+	e, err := f.lookup("stat", name)
+	if err != nil {
+		return nil, err
+	}
+	return info{e}, nil
+}
+
+// Close stops the cat-file process, if one was started
+func (f *FS) Close() error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	
+	if f.batch == nil {
+		return nil
+	}
+	f.stdin.Close()
+	err := f.batch.Wait()
+	f.batch = nil
+	return err
+}
+
+func (f *FS) lookup(op, name string) (*entry, error) {
+	if !fs.ValidPath(name) {
+		return nil, &fs.PathError{Op: op, Path: name, Err: fs.ErrInvalid}
+	}
+	e, ok := f.entries[name]
+	if !ok {
+		return nil, &fs.PathError{Op: op, Path: name, Err: fs.ErrNotExist}
+	}
+	return e, nil
+}
+
+// readBlob reads an object through the shared cat-file process, starting it
+// on first use
+func (f *FS) readBlob(hash string) ([]byte, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	
+	if f.batch == nil {
+		if err := f.startBatch(); err != nil {
+			return nil, err
+		}
+	}
+	
+	if _, err := fmt.Fprintln(f.stdin, hash); err != nil {
+		return nil, err
+	}
+	
+	// <object> SP <type> SP <size> LF <contents> LF
+	header, err := f.stdout.ReadString('\n')
+	if err != nil {
+		return nil, err
+	}
+	fields := strings.Fields(header)
+	if len(fields) != 3 {
+		return nil, fmt.Errorf("cat-file: %s", strings.TrimSpace(header))
+	}
+	size, err := strconv.Atoi(fields[2])
+	if err != nil {
+		return nil, fmt.Errorf("cat-file: %s", strings.TrimSpace(header))
+	}
+	
+	data := make([]byte, size+1)
+	if _, err := io.ReadFull(f.stdout, data); err != nil {
+		return nil, err
+	}
+	return data[:size], nil
+}
+
+func (f *FS) startBatch() error {
+	cmd := exec.Command("git", "-C", f.dir, "cat-file", "--batch")
+	stdin, err := cmd.StdinPipe()
+	if err != nil {
+		return err
+	}
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return err
+	}
+	if err := cmd.Start(); err != nil {
+		return err
+	}
+	
+	f.batch = cmd
+	f.stdin = stdin
+	f.stdout = bufio.NewReader(stdout)
+	return nil
+}
+
+// Git runs a git command in dir and returns its output without the final
+// newline. Errors include git's own message.
+func Git(dir string, args ...string) (string, error) {
+	cmd := exec.Command("git", append([]string{"-C", dir}, args...)...)
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	if err != nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return "", fmt.Errorf("git %s: %s", args[0], msg)
+		}
+		return "", fmt.Errorf("git %s: %v", args[0], err)
+	}
+	return strings.TrimRight(string(out), "\n"), nil
+}
+
+// info describes an entry (fs.FileInfo and fs.DirEntry)
+type info struct {
+	*entry
+}
+
+func (i info) Name() string               { return i.name }
+func (i info) Size() int64                { return i.size }
+func (i info) Mode() fs.FileMode          { return i.mode }
+func (i info) Type() fs.FileMode          { return i.mode.Type() }
+func (i info) ModTime() time.Time         { return time.Time{} }
+func (i info) IsDir() bool                { return i.mode.IsDir() }
+func (i info) Sys() any                   { return nil }
+func (i info) Info() (fs.FileInfo, error) { return i, nil }
+
+func dirEntries(entries []*entry) []fs.DirEntry {
+	list := make([]fs.DirEntry, len(entries))
+	for i, e := range entries {
+		list[i] = info{e}
+	}
+	return list
+}
+
+// file is an open file whose contents have been read into memory
+type file struct {
+	info
+	*bytes.Reader
+}
+
+func (f *file) Stat() (fs.FileInfo, error) { return f.info, nil }
+func (f *file) Close() error               { return nil }
+
+// dir is an open directory (fs.ReadDirFile)
+type dir struct {
+	info
+	offset int
+}
+
+func (d *dir) Stat() (fs.FileInfo, error) { return d.info, nil }
+func (d *dir) Close() error               { return nil }
+
+func (d *dir) Read([]byte) (int, error) {
+	return 0, &fs.PathError{Op: "read", Path: d.name, Err: errors.New("is a directory")}
+}
+
+func (d *dir) ReadDir(n int) ([]fs.DirEntry, error) {
+	remaining := d.children[d.offset:]
+	if n > 0 {
+		if len(remaining) == 0 {
+			return nil, io.EOF
+		}
+		if n < len(remaining) {
+			remaining = remaining[:n]
+		}
+	}
+	d.offset += len(remaining)
+	return dirEntries(remaining), nil
+}
\ No newline at end of file
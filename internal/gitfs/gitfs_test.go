@@ -0,0 +1,117 @@
+package gitfs
+
+import (
+	"errors"
+	"io/fs"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+	"testing/fstest"
+)
+
+// initRepo creates a repository with files committed and returns its path
+func initRepo(t *testing.T, files map[string]string) string {
+	t.Helper()
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not installed")
+	}
+	
+	dir := t.TempDir()
+	run(t, dir, "init", "-q")
+	for name, content := range files {
+		path := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	run(t, dir, "add", "-A")
+	run(t, dir, "-c", "user.name=Test", "-c", "user.email=test@example.com", "commit", "-q", "-m", "initial")
+	return dir
+}
+
+func run(t *testing.T, dir string, args ...string) {
+	t.Helper()
+	cmd := exec.Command("git", append([]string{"-C", dir}, args...)...)
+	if out, err := cmd.CombinedOutput(); err != nil {
+		t.Fatalf("git %v failed: %v\n%s", args, err, out)
+	}
+}
+
+func TestOpen_ReadsCommittedTree(t *testing.T) {
+	dir := initRepo(t, map[string]string{
+		"main.go":         "package main\n",
+		"pkg/util.go":     "package pkg\n\nfunc Util() {}\n",
+		"pkg.go":          "package x\n", // Sorts between "pkg" and "pkg/" in git order
+		"docs/README.md":  "# Docs\n",
+		"docs/a/b/c.txt":  "deep\n",
+		"scripts/run.sh":  "#!/bin/sh\necho hi\n",
+		"scripts/.hidden": "x\n",
+	})
+	
+	// Working copy changes must not be visible
+	os.WriteFile(filepath.Join(dir, "main.go"), []byte("changed\n"), 0o644)
+	os.WriteFile(filepath.Join(dir, "untracked.go"), []byte("new\n"), 0o644)
+	
+	fsys, err := Open(dir, "HEAD")
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	defer fsys.Close()
+	
+	if err := fstest.TestFS(fsys, "main.go", "pkg/util.go", "pkg.go", "docs/a/b/c.txt", "scripts/.hidden"); err != nil {
+		t.Fatal(err)
+	}
+	
+	data, err := fs.ReadFile(fsys, "main.go")
+	if err != nil || string(data) != "package main\n" {
+		t.Errorf("Expected committed contents, got %q (%v)", data, err)
+	}
+	if _, err := fs.Stat(fsys, "untracked.go"); err == nil {
+		t.Error("Expected untracked files to be missing")
+	}
+}
+
+func TestOpen_Subdirectory(t *testing.T) {
+	dir := initRepo(t, map[string]string{
+		"main.go":     "package main\n",
+		"pkg/util.go": "package pkg\n",
+	})
+	
+	fsys, err := Open(filepath.Join(dir, "pkg"), "HEAD")
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	defer fsys.Close()
+	
+	entries, err := fs.ReadDir(fsys, ".")
+	if err != nil || len(entries) != 1 || entries[0].Name() != "util.go" {
+		t.Errorf("Expected only util.go, got %v (%v)", entries, err)
+	}
+}
+
+func TestOpen_UnknownRevision(t *testing.T) {
+	dir := initRepo(t, map[string]string{"main.go": "package main\n"})
+	
+	if _, err := Open(dir, "no-such-branch"); err == nil {
+		t.Error("Expected error for unknown revision, got nil")
+	}
+}
+
+func TestOpen_MissingDirectory(t *testing.T) {
+	dir := initRepo(t, map[string]string{"main.go": "package main\n"})
+	pkg := filepath.Join(dir, "pkg")
+	if err := os.MkdirAll(pkg, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	
+	if _, err := Open(pkg, "HEAD"); !errors.Is(err, ErrNotExist) {
+		t.Errorf("Expected ErrNotExist for a directory added since, got %v", err)
+	}
+	if _, err := Open(dir, "no-such-branch"); errors.Is(err, ErrNotExist) {
+		t.Errorf("Expected an unknown revision not to be ErrNotExist, got %v", err)
+	}
+}
\ No newline at end of file
@@ -0,0 +1,287 @@
+package history
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+	
+	"github.com/user/loctree/internal/gitfs"
+	"github.com/user/loctree/internal/tree"
+)
+
+// Step is the interval between samples
+type Step int
+
+const (
+	Daily Step = iota
+	Weekly
+	Monthly
+)
+
+var stepNames = []string{"daily", "weekly", "monthly"}
+
+// String returns the name of the step
+func (s Step) String() string {
+	if s < 0 || int(s) >= len(stepNames) {
+		return fmt.Sprintf("Step(%d)", int(s))
+	}
+	return stepNames[s]
+}
+
+// ParseStep converts a step name into a Step
+func ParseStep(name string) (Step, error) {
+	for i, stepName := range stepNames {
+		if name == stepName {
+			return Step(i), nil
+		}
+	}
+	return Weekly, fmt.Errorf("unknown step %q (expected daily, weekly or monthly)", name)
+}
+
+// previous returns the sample time that precedes t
+func (s Step) previous(t time.Time) time.Time {
+	switch s {
+	case Daily:
+		return t.AddDate(0, 0, -1)
+	case Monthly:
+		return t.AddDate(0, -1, 0)
+	default:
+		return t.AddDate(0, 0, -7)
+	}
+}
+
+// ParseSince converts a period such as "30d", "2w", "6m" or "1y", or a date
+// such as "2024-01-31", into the time it starts
+func ParseSince(value string, now time.Time) (time.Time, error) {
+	if date, err := time.ParseInLocation("2006-01-02", value, now.Location()); err == nil {
+		return date, nil
+	}
+	
+	if len(value) < 2 {
+		return time.Time{}, fmt.Errorf("invalid period %q (expected e.g. 30d, 2w, 6m, 1y or 2024-01-31)", value)
+	}
+	n, err := strconv.Atoi(value[:len(value)-1])
+	if err != nil || n < 0 {
+		return time.Time{}, fmt.Errorf("invalid period %q (expected e.g. 30d, 2w, 6m, 1y or 2024-01-31)", value)
+	}
+	
+	switch value[len(value)-1] {
+	case 'd':
+		return now.AddDate(0, 0, -n), nil
+	case 'w':
+		return now.AddDate(0, 0, -7*n), nil
+	case 'm':
+		return now.AddDate(0, -n, 0), nil
+	case 'y':
+		return now.AddDate(-n, 0, 0), nil
+	default:
+		return time.Time{}, fmt.Errorf("invalid period %q (expected e.g. 30d, 2w, 6m, 1y or 2024-01-31)", value)
+	}
+}
+
+// Sample is a point in time and the commit that was current then
+type Sample struct {
+	Time   time.Time
+	Commit string
+}
+
+// Options controls how history is sampled
+type Options struct {
+	Rev      string                // Revision whose first-parent history is sampled; empty for HEAD
+	Since    time.Time             // First sample
+	Until    time.Time             // Last sample; zero for now
+	Step     Step                  // Interval between samples
+	Metric   tree.Metric           // Line count recorded for each directory
+	Build    tree.Options          // Options for building each sampled tree (FS is set per sample)
+	Progress func(done, total int) // Called after each commit is scanned; may be nil
+}
+
+// History is a time series of line counts for every directory of a tree
+type History struct {
+	Root    string
+	Step    Step
+	Metric  tree.Metric
+	Samples []Sample
+	Counts  map[string][]int // Count at every sample, keyed by path relative to the root ("." for the root)
+}
+
+// Series returns the counts of a directory at every sample; zero where it
+// did not exist. A nil History has no series.
+func (h *History) Series(relPath string) []int {
+	if h == nil {
+		return nil
+	}
+	return h.Counts[relPath]
+}
+
+// Paths returns every directory that existed at any sample, sorted
+func (h *History) Paths() []string {
+	paths := make([]string, 0, len(h.Counts))
+	for p := range h.Counts {
+		paths = append(paths, p)
+	}
+	sort.Strings(paths)
+	return paths
+}
+
+// Build samples the git history of dir and builds a tree for every sampled
+// commit. Commits shared by several samples are scanned once.
+func Build(dir string, opts Options) (*History, error) {
+	until := opts.Until
+	if until.IsZero() {
+		until = time.Now()
+	}
+	
+	times := sampleTimes(opts.Since, until, opts.Step)
+	if len(times) == 0 {
+		return nil, fmt.Errorf("history starts after %s", until.Format("2006-01-02"))
+	}
+	samples, err := sampleCommits(dir, opts.Rev, times)
+	if err != nil {
+		return nil, err
+	}
+	
+	h := &History{
+		Root:    dir,
+		Step:    opts.Step,
+		Metric:  opts.Metric,
+		Samples: samples,
+		Counts:  make(map[string][]int),
+	}
+	
+	counted := make(map[string]map[string]int)
+	for i, sample := range samples {
+		counts, ok := counted[sample.Commit]
+		if !ok {
+			counts, err = countCommit(dir, sample.Commit, opts)
+			if err != nil {
+				return nil, err
+			}
+			counted[sample.Commit] = counts
+		}
+		for relPath, count := range counts {
+			if h.Counts[relPath] == nil {
+				h.Counts[relPath] = make([]int, len(samples))
+			}
+			h.Counts[relPath][i] = count
+		}
+		if opts.Progress != nil {
+			opts.Progress(i+1, len(samples))
+		}
+	}
+	
+	return h, nil
+}
+
+// countCommit builds the tree of dir at a commit and records every directory's count
+func countCommit(dir, commit string, opts Options) (map[string]int, error) {
+	fsys, err := gitfs.Open(dir, commit)
+	if errors.Is(err, gitfs.ErrNotExist) {
+		// The directory did not exist yet
+		return map[string]int{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer fsys.Close()
+	
+	build := opts.Build
+	build.FS = fsys
+	root, err := tree.BuildTreeWithOptions(dir, build)
+	if err != nil {
+		return nil, err
+	}
+	
+	counts := make(map[string]int)
+	var record func(node *tree.DirectoryNode, relPath string)
+	record = func(node *tree.DirectoryNode, relPath string) {
+		counts[relPath] = node.Count(opts.Metric)
+		for _, child := range node.Children {
+			if relPath == "." {
+				record(child, child.Name)
+			} else {
+				record(child, relPath+"/"+child.Name)
+			}
+		}
+	}
+	record(root, ".")
+	return counts, nil
+}
+
+// sampleTimes returns the times from since to until, one step apart and
+// counted back from until so that the last sample is always until
+func sampleTimes(since, until time.Time, step Step) []time.Time {
+	var times []time.Time
+	for t := until; !t.Before(since); t = step.previous(t) {
+		times = append(times, t)
+	}
+	for i, j := 0, len(times)-1; i < j; i, j = i+1, j-1 {
+		times[i], times[j] = times[j], times[i]
+	}
+	return times
+}
+
+// commit is a first-parent commit and its committer time
+type commit struct {
+	hash string
+	time time.Time
+}
+
+// sampleCommits picks the newest first-parent commit at or before each time.
+// Times before the first commit are dropped.
+func sampleCommits(dir, rev string, times []time.Time) ([]Sample, error) {
+	if rev == "" {
+		rev = "HEAD"
+	}
+	commits, err := firstParentCommits(dir, rev)
+	if err != nil {
+		return nil, err
+	}
+	
+	var samples []Sample
+	for _, t := range times {
+		// Index of the first commit after t
+		i := sort.Search(len(commits), func(i int) bool {
+			return commits[i].time.After(t)
+		})
+		if i == 0 {
+			continue
+		}
+		samples = append(samples, Sample{Time: t, Commit: commits[i-1].hash})
+	}
+	if len(samples) == 0 {
+		return nil, fmt.Errorf("no commits in %s before %s", dir, times[len(times)-1].Format("2006-01-02"))
+	}
+	return samples, nil
+}
+
+// firstParentCommits lists the first-parent history of rev, oldest first
+func firstParentCommits(dir, rev string) ([]commit, error) {
+	out, err := gitfs.Git(dir, "log", "--first-parent", "--format=%H %ct", rev, "--")
+	if err != nil {
+		return nil, err
+	}
+	
+	var commits []commit
+	for _, line := range strings.Split(out, "\n") {
+		hash, seconds, ok := strings.Cut(line, " ")
+		if !ok {
+			continue
+		}
+		unix, err := strconv.ParseInt(seconds, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("unexpected git log output: %q", line)
+		}
+		commits = append(commits, commit{hash: hash, time: time.Unix(unix, 0)})
+	}
+	
+	// git log lists the newest commit first; committer times are not always
+	// monotonic, so sort rather than reverse
+	sort.SliceStable(commits, func(i, j int) bool {
+		return commits[i].time.Before(commits[j].time)
+	})
+	return commits, nil
+}
\ No newline at end of file
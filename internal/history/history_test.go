@@ -0,0 +1,164 @@
+package history
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+	"time"
+	
+	"github.com/user/loctree/internal/tree"
+)
+
+var now = time.Date(2026, 6, 15, 12, 0, 0, 0, time.UTC)
+
+func TestParseSince(t *testing.T) {
+	cases := map[string]time.Time{
+		"30d":        now.AddDate(0, 0, -30),
+		"2w":         now.AddDate(0, 0, -14),
+		"6m":         now.AddDate(0, -6, 0),
+		"1y":         now.AddDate(-1, 0, 0),
+		"2026-01-31": time.Date(2026, 1, 31, 0, 0, 0, 0, time.UTC),
+	}
+	for value, expected := range cases {
+		got, err := ParseSince(value, now)
+		if err != nil || !got.Equal(expected) {
+			t.Errorf("ParseSince(%q): expected %v, got %v (%v)", value, expected, got, err)
+		}
+	}
+	
+	for _, value := range []string{"", "m", "6x", "-1d", "yesterday"} {
+		if _, err := ParseSince(value, now); err == nil {
+			t.Errorf("Expected error for %q, got nil", value)
+		}
+	}
+}
+
+func TestParseStep(t *testing.T) {
+	for _, name := range []string{"daily", "weekly", "monthly"} {
+		step, err := ParseStep(name)
+		if err != nil || step.String() != name {
+			t.Errorf("Expected step %q, got %s (%v)", name, step, err)
+		}
+	}
+	if _, err := ParseStep("hourly"); err == nil {
+		t.Error("Expected error for unknown step, got nil")
+	}
+}
+
+func TestSampleTimes(t *testing.T) {
+	times := sampleTimes(now.AddDate(0, 0, -20), now, Weekly)
+	
+	// Counted back from now: -14d, -7d, now
+	if len(times) != 3 {
+		t.Fatalf("Expected 3 samples, got %d", len(times))
+	}
+	if !times[0].Equal(now.AddDate(0, 0, -14)) || !times[2].Equal(now) {
+		t.Errorf("Unexpected sample times: %v", times)
+	}
+}
+
+// commitAt writes files and commits them with the given committer date
+func commitAt(t *testing.T, dir string, date time.Time, files map[string]string) {
+	t.Helper()
+	for name, content := range files {
+		path := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	
+	stamp := date.Format(time.RFC3339)
+	for _, args := range [][]string{
+		{"add", "-A"},
+		{"-c", "user.name=Test", "-c", "user.email=test@example.com", "commit", "-q", "-m", stamp},
+	} {
+		cmd := exec.Command("git", append([]string{"-C", dir}, args...)...)
+		cmd.Env = append(os.Environ(), "GIT_AUTHOR_DATE="+stamp, "GIT_COMMITTER_DATE="+stamp)
+		if out, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("git %v failed: %v\n%s", args, err, out)
+		}
+	}
+}
+
+func TestBuild(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not installed")
+	}
+	dir := t.TempDir()
+	if out, err := exec.Command("git", "-C", dir, "init", "-q").CombinedOutput(); err != nil {
+		t.Fatalf("git init failed: %v\n%s", err, out)
+	}
+	
+	commitAt(t, dir, now.AddDate(0, 0, -20), map[string]string{"main.go": "package main\n"})
+	commitAt(t, dir, now.AddDate(0, 0, -10), map[string]string{"pkg/util.go": "package pkg\n\nfunc A() {}\n"})
+	commitAt(t, dir, now.AddDate(0, 0, -3), map[string]string{"pkg/more.go": "package pkg\n"})
+	
+	var progress int
+	h, err := Build(dir, Options{
+		Since:    now.AddDate(0, 0, -21),
+		Until:    now,
+		Step:     Weekly,
+		Metric:   tree.MetricLines,
+		Progress: func(done, total int) { progress = done },
+	})
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	
+	// Samples at -21d (before the first commit, dropped), -14d, -7d and now
+	if len(h.Samples) != 3 || progress != 3 {
+		t.Fatalf("Expected 3 samples, got %d (progress %d)", len(h.Samples), progress)
+	}
+	
+	expected := map[string][]int{
+		".":   {1, 4, 5},
+		"pkg": {0, 3, 4},
+	}
+	for path, counts := range expected {
+		series := h.Series(path)
+		if len(series) != len(counts) {
+			t.Fatalf("Expected %d counts for %s, got %v", len(counts), path, series)
+		}
+		for i := range counts {
+			if series[i] != counts[i] {
+				t.Errorf("Expected %s counts %v, got %v", path, counts, series)
+				break
+			}
+		}
+	}
+	
+	var none *History
+	if none.Series(".") != nil {
+		t.Error("Expected a nil history to have no series")
+	}
+}
+
+func TestBuild_Subdirectory(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not installed")
+	}
+	dir := t.TempDir()
+	if out, err := exec.Command("git", "-C", dir, "init", "-q").CombinedOutput(); err != nil {
+		t.Fatalf("git init failed: %v\n%s", err, out)
+	}
+	commitAt(t, dir, now.AddDate(0, 0, -20), map[string]string{"main.go": "package main\n"})
+	commitAt(t, dir, now.AddDate(0, 0, -3), map[string]string{"pkg/util.go": "package pkg\n"})
+	
+	// pkg did not exist at the first sample, which counts as empty
+	h, err := Build(filepath.Join(dir, "pkg"), Options{Since: now.AddDate(0, 0, -14), Until: now, Step: Weekly})
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if series := h.Series("."); len(series) != 3 || series[0] != 0 || series[2] != 1 {
+		t.Errorf("Expected 0 lines before pkg was added and 1 after, got %v", series)
+	}
+	
+	// Other git errors are reported
+	if _, err := countCommit(dir, "0000000000000000000000000000000000000000", Options{}); err == nil {
+		t.Error("Expected error for a missing commit, got nil")
+	}
+}
\ No newline at end of file
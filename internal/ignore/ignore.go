@@ -0,0 +1,243 @@
+package ignore
+
+import (
+	"bufio"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// FileNames lists the ignore files read in every directory, lowest precedence first
+var FileNames = []string{".gitignore", ".ignore", ".loctreeignore"}
+
+// pattern is a single compiled ignore rule
+type pattern struct {
+	regex   *regexp.Regexp
+	negate  bool // Pattern started with "!" and re-includes matches
+	dirOnly bool // Pattern ended with "/" and only matches directories
+}
+
+// Matcher applies gitignore rules collected from nested ignore files.
+// Paths are relative to the walk root and use forward slashes.
+type Matcher struct {
+	patterns map[string][]pattern // Patterns keyed by the directory of their ignore file
+}
+
+// New creates an empty matcher
+func New() *Matcher {
+	return &Matcher{
+		patterns: make(map[string][]pattern),
+	}
+}
+
+// LoadDir reads the ignore files of a directory on disk. dirPath is the
+// directory's location and relDir its path relative to the walk root.
+func (m *Matcher) LoadDir(dirPath, relDir string) {
+	for _, name := range FileNames {
+		file, err := os.Open(filepath.Join(dirPath, name))
+		if err != nil {
+			continue // Missing or unreadable ignore files are skipped
+		}
+		m.AddPatterns(relDir, file)
+		file.Close()
+	}
+}
+
+// LoadFS reads the ignore files of a directory in fsys. relDir is the
+// directory's slash-separated path relative to the root of fsys ("" or ".").
+func (m *Matcher) LoadFS(fsys fs.FS, relDir string) {
+	for _, name := range FileNames {
+		file, err := fsys.Open(path.Join(cleanDir(relDir), name))
+		if err != nil {
+			continue // Missing or unreadable ignore files are skipped
+		}
+		m.AddPatterns(relDir, file)
+		file.Close()
+	}
+}
+
+// AddPatterns parses gitignore rules from r. The rules are relative to dir,
+// the directory containing the ignore file ("" for the walk root).
+func (m *Matcher) AddPatterns(dir string, r io.Reader) {
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		m.AddPattern(dir, scanner.Text())
+	}
+}
+
+// AddPattern adds a single gitignore rule relative to dir
+func (m *Matcher) AddPattern(dir, line string) {
+	if p, ok := parsePattern(line); ok {
+		dir = cleanDir(dir)
+		m.patterns[dir] = append(m.patterns[dir], p)
+	}
+}
+
+// Forget drops the rules read from the ignore files of dir, e.g. before
+// reading them again after they changed
+func (m *Matcher) Forget(dir string) {
+	delete(m.patterns, cleanDir(dir))
+}
+
+// Match reports whether relPath is ignored. Rules from deeper directories
+// override those of their ancestors and, within a directory, the last matching
+// rule wins. Callers walking a tree should not descend into ignored directories,
+// since git never re-includes files below an ignored directory.
+func (m *Matcher) Match(relPath string, isDir bool) bool {
+	if len(m.patterns) == 0 {
+		return false
+	}
+	
+	relPath = strings.Trim(filepath.ToSlash(relPath), "/")
+	ignored := false
+	
+	// Visit the ignore files of every ancestor directory, root first
+	dir := ""
+	rest := relPath
+	for {
+		for _, p := range m.patterns[dir] {
+			if p.dirOnly && !isDir {
+				continue
+			}
+			if p.regex.MatchString(rest) {
+				ignored = !p.negate
+			}
+		}
+		
+		segment, remainder, found := strings.Cut(rest, "/")
+		if !found {
+			break
+		}
+		dir = path.Join(dir, segment)
+		rest = remainder
+	}
+	
+	return ignored
+}
+
+// parsePattern compiles one line of an ignore file
+func parsePattern(line string) (pattern, bool) {
+	line = strings.TrimSuffix(line, "\r")
+	if line == "" || strings.HasPrefix(line, "#") {
+		return pattern{}, false
+	}
+	
+	// Trailing spaces are ignored unless escaped with a backslash
+	for strings.HasSuffix(line, " ") && !strings.HasSuffix(line, "\\ ") {
+		line = strings.TrimSuffix(line, " ")
+	}
+	
+	p := pattern{}
+	if strings.HasPrefix(line, "!") {
+		p.negate = true
+		line = line[1:]
+	} else if strings.HasPrefix(line, "\\!") || strings.HasPrefix(line, "\\#") {
+		line = line[1:]
+	}
+	
+	if strings.HasSuffix(line, "/") {
+		p.dirOnly = true
+		line = strings.TrimRight(line, "/")
+	}
+	if line == "" {
+		return pattern{}, false
+	}
+	
+	// A slash anywhere but the end anchors the pattern to its directory
+	anchored := strings.Contains(line, "/")
+	line = strings.TrimPrefix(line, "/")
+	
+	expr := globToRegex(line)
+	if !anchored {
+		expr = "(?:.*/)?" + expr
+	}
+	
+	regex, err := regexp.Compile("^" + expr + "$")
+	if err != nil {
+		return pattern{}, false
+	}
+	p.regex = regex
+	
+	return p, true
+}
+
+// globToRegex converts a slash-separated glob, including "**" segments, into a regular expression
+func globToRegex(glob string) string {
+	segments := strings.Split(glob, "/")
+	var b strings.Builder
+	needSeparator := false
+	
+	for i, segment := range segments {
+		if segment == "**" {
+			switch {
+			case len(segments) == 1:
+				b.WriteString(".*") // Matches everything
+			case i == 0:
+				b.WriteString("(?:.*/)?") // Leading "**/" matches in all directories
+			case i == len(segments)-1:
+				b.WriteString("/.*") // Trailing "/**" matches everything inside
+			default:
+				b.WriteString("(?:/.*)?") // "/**/" matches zero or more directories
+			}
+			needSeparator = i != 0
+			continue
+		}
+		
+		if needSeparator {
+			b.WriteString("/")
+		}
+		b.WriteString(segmentToRegex(segment))
+		needSeparator = true
+	}
+	
+	return b.String()
+}
+
+// segmentToRegex converts a glob for a single path segment into a regular expression
+func segmentToRegex(segment string) string {
+	var b strings.Builder
+	
+	for i := 0; i < len(segment); i++ {
+		c := segment[i]
+		switch c {
+		case '\\':
+			if i+1 < len(segment) {
+				i++
+				b.WriteString(regexp.QuoteMeta(string(segment[i])))
+			}
+		case '*':
+			b.WriteString("[^/]*")
+		case '?':
+			b.WriteString("[^/]")
+		case '[':
+			end := strings.IndexByte(segment[i+1:], ']')
+			if end < 0 {
+				b.WriteString(`\[`)
+				continue
+			}
+			class := segment[i+1 : i+1+end]
+			i += end + 1
+			if strings.HasPrefix(class, "!") || strings.HasPrefix(class, "^") {
+				class = "^" + class[1:]
+			}
+			b.WriteString("[" + strings.ReplaceAll(class, `\`, `\\`) + "]")
+		default:
+			b.WriteString(regexp.QuoteMeta(string(c)))
+		}
+	}
+	
+	return b.String()
+}
+
+// cleanDir normalises a relative directory to slash form, "" for the root
+func cleanDir(dir string) string {
+	dir = strings.Trim(filepath.ToSlash(dir), "/")
+	if dir == "." {
+		return ""
+	}
+	return dir
+}
\ No newline at end of file
@@ -0,0 +1,168 @@
+package ignore
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func newMatcher(rules map[string]string) *Matcher {
+	m := New()
+	for dir, content := range rules {
+		m.AddPatterns(dir, strings.NewReader(content))
+	}
+	return m
+}
+
+type matchCase struct {
+	path     string
+	isDir    bool
+	expected bool
+}
+
+func assertMatches(t *testing.T, m *Matcher, cases []matchCase) {
+	t.Helper()
+	for _, c := range cases {
+		if got := m.Match(c.path, c.isDir); got != c.expected {
+			t.Errorf("Match(%q, isDir=%v): expected %v, got %v", c.path, c.isDir, c.expected, got)
+		}
+	}
+}
+
+func TestMatch_NameAtAnyDepth(t *testing.T) {
+	m := newMatcher(map[string]string{"": "node_modules\n*.log\n"})
+	
+	assertMatches(t, m, []matchCase{
+		{"node_modules", true, true},
+		{"web/node_modules", true, true},
+		{"debug.log", false, true},
+		{"logs/app/debug.log", false, true},
+		{"main.go", false, false},
+		{"node_modules_backup", true, false},
+	})
+}
+
+func TestMatch_CommentsAndBlankLines(t *testing.T) {
+	m := newMatcher(map[string]string{"": "# comment\n\n\\#literal\n"})
+	
+	assertMatches(t, m, []matchCase{
+		{"# comment", false, false},
+		{"#literal", false, true},
+	})
+}
+
+func TestMatch_Negation(t *testing.T) {
+	m := newMatcher(map[string]string{"": "*.log\n!keep.log\n"})
+	
+	assertMatches(t, m, []matchCase{
+		{"debug.log", false, true},
+		{"keep.log", false, false},
+		{"sub/keep.log", false, false},
+	})
+}
+
+func TestMatch_DirectoryOnly(t *testing.T) {
+	m := newMatcher(map[string]string{"": "build/\n"})
+	
+	assertMatches(t, m, []matchCase{
+		{"build", true, true},
+		{"src/build", true, true},
+		{"build", false, false},
+	})
+}
+
+func TestMatch_Anchored(t *testing.T) {
+	m := newMatcher(map[string]string{"": "/dist\ndocs/generated\n"})
+	
+	assertMatches(t, m, []matchCase{
+		{"dist", true, true},
+		{"web/dist", true, false},
+		{"docs/generated", true, true},
+		{"src/docs/generated", true, false},
+	})
+}
+
+func TestMatch_DoubleStar(t *testing.T) {
+	m := newMatcher(map[string]string{"": "**/testdata\nvendor/**\na/**/z.txt\n"})
+	
+	assertMatches(t, m, []matchCase{
+		{"testdata", true, true},
+		{"internal/scanner/testdata", true, true},
+		{"vendor/github.com/lib.go", false, true},
+		{"vendor", true, false},
+		{"a/z.txt", false, true},
+		{"a/b/c/z.txt", false, true},
+		{"b/a/z.txt", false, false},
+	})
+}
+
+func TestMatch_WildcardsAndClasses(t *testing.T) {
+	m := newMatcher(map[string]string{"": "file?.txt\n*.[oa]\nlib[!x].go\n"})
+	
+	assertMatches(t, m, []matchCase{
+		{"file1.txt", false, true},
+		{"file10.txt", false, false},
+		{"main.o", false, true},
+		{"libfoo.a", false, true},
+		{"main.c", false, false},
+		{"liby.go", false, true},
+		{"libx.go", false, false},
+	})
+}
+
+func TestMatch_StarDoesNotCrossDirectories(t *testing.T) {
+	m := newMatcher(map[string]string{"": "docs/*.md\n"})
+	
+	assertMatches(t, m, []matchCase{
+		{"docs/readme.md", false, true},
+		{"docs/api/readme.md", false, false},
+	})
+}
+
+func TestMatch_NestedIgnoreFiles(t *testing.T) {
+	m := newMatcher(map[string]string{
+		"":    "*.gen.go\n",
+		"api": "!schema.gen.go\n/local\n",
+	})
+	
+	assertMatches(t, m, []matchCase{
+		{"types.gen.go", false, true},
+		{"api/types.gen.go", false, true},
+		{"api/schema.gen.go", false, false},
+		{"schema.gen.go", false, true},
+		{"api/local", true, true},
+		{"local", true, false},
+		{"web/api/local", true, false},
+	})
+}
+
+func TestMatch_NoPatterns(t *testing.T) {
+	m := New()
+	if m.Match("anything", false) {
+		t.Error("Expected empty matcher to ignore nothing")
+	}
+}
+
+func TestLoadDir_ReadsAllIgnoreFiles(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		".gitignore":     "*.log\n",
+		".ignore":        "vendor/\n",
+		".loctreeignore": "!important.log\n",
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	
+	m := New()
+	m.LoadDir(dir, "")
+	
+	assertMatches(t, m, []matchCase{
+		{"debug.log", false, true},
+		{"vendor", true, true},
+		{"important.log", false, false},
+	})
+}
\ No newline at end of file
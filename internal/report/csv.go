@@ -0,0 +1,45 @@
+package report
+
+import (
+	"encoding/csv"
+	"io"
+	"strconv"
+	
+	"github.com/user/loctree/internal/tree"
+)
+
+// csvHeader names the columns written by WriteCSV
+var csvHeader = []string{"path", "type", "depth", "loc", "file_loc", "code", "comments", "blanks"}
+
+// WriteCSV writes one row per reported directory (and file, when included)
+// with its path relative to the root, depth and line counts
+func WriteCSV(w io.Writer, root *tree.DirectoryNode, opts Options) error {
+	writer := csv.NewWriter(w)
+	if err := writer.Write(csvHeader); err != nil {
+		return err
+	}
+	
+	for _, node := range visibleNodes(root, opts) {
+		nodeType := TypeDirectory
+		if node.IsFile {
+			nodeType = TypeFile
+		}
+		
+		record := []string{
+			node.RelativePath(),
+			nodeType,
+			strconv.Itoa(node.Depth()),
+			strconv.Itoa(node.LOC),
+			strconv.Itoa(node.FileLOC),
+			strconv.Itoa(node.Code),
+			strconv.Itoa(node.Comments),
+			strconv.Itoa(node.Blanks),
+		}
+		if err := writer.Write(record); err != nil {
+			return err
+		}
+	}
+	
+	writer.Flush()
+	return writer.Error()
+}
\ No newline at end of file
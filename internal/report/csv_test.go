@@ -0,0 +1,54 @@
+package report
+
+import (
+	"bytes"
+	"encoding/csv"
+	"testing"
+	
+	"github.com/user/loctree/internal/tree"
+)
+
+func TestWriteCSV(t *testing.T) {
+	var buf bytes.Buffer
+	if err := WriteCSV(&buf, sampleTree(), Options{MaxDepth: tree.AllDepths}); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	
+	records, err := csv.NewReader(&buf).ReadAll()
+	if err != nil {
+		t.Fatalf("Expected valid CSV, got: %v", err)
+	}
+	if len(records) != 3 {
+		t.Fatalf("Expected header and 2 directory rows, got %d rows", len(records))
+	}
+	if records[0][0] != "path" || records[0][3] != "loc" {
+		t.Errorf("Unexpected header: %v", records[0])
+	}
+	
+	root := []string{".", "directory", "0", "15", "10", "12", "1", "2"}
+	pkg := []string{"pkg", "directory", "1", "5", "5", "4", "1", "0"}
+	for i, expected := range [][]string{root, pkg} {
+		for j := range expected {
+			if records[i+1][j] != expected[j] {
+				t.Errorf("Expected row %v, got %v", expected, records[i+1])
+				break
+			}
+		}
+	}
+}
+
+func TestWriteCSV_Depth(t *testing.T) {
+	var buf bytes.Buffer
+	if err := WriteCSV(&buf, sampleTree(), Options{MaxDepth: 1, IncludeFiles: true}); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	
+	records, err := csv.NewReader(&buf).ReadAll()
+	if err != nil {
+		t.Fatalf("Expected valid CSV, got: %v", err)
+	}
+	// Header, root, pkg (collapsed) and main.go
+	if len(records) != 4 {
+		t.Errorf("Expected 4 rows, got %d", len(records))
+	}
+}
\ No newline at end of file
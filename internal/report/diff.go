@@ -0,0 +1,122 @@
+package report
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"text/tabwriter"
+	"time"
+	
+	"github.com/user/loctree/internal/format"
+	"github.com/user/loctree/internal/tree"
+)
+
+// DiffFormats lists the --format values supported by the diff command
+var DiffFormats = []string{FormatTUI, FormatText, FormatJSON}
+
+// DiffDocument is the JSON document written by diff --format json
+type DiffDocument struct {
+	SchemaVersion int            `json:"schema_version"`
+	Old           string         `json:"old"`          // Root of the earlier scan
+	New           string         `json:"new"`          // Root of the newer scan
+	GeneratedAt   time.Time      `json:"generated_at"` // When the comparison was made
+	Changes       []*ChangeEntry `json:"changes"`      // Nodes that differ, in tree order
+}
+
+// ChangeEntry is a directory or file that differs between the two scans
+type ChangeEntry struct {
+	Path    string `json:"path"`
+	Type    string `json:"type"`   // TypeDirectory or TypeFile
+	Status  string `json:"status"` // added, removed or changed
+	OldLOC  int    `json:"old_loc"`
+	NewLOC  int    `json:"new_loc"`
+	Added   int    `json:"added"`   // Lines gained (including children)
+	Removed int    `json:"removed"` // Lines lost (including children)
+}
+
+// IsDiffFormat reports whether name is a --format value supported by diff
+func IsDiffFormat(name string) bool {
+	for _, format := range DiffFormats {
+		if name == format {
+			return true
+		}
+	}
+	return false
+}
+
+// WriteDiff writes the nodes of a tree built by tree.Diff that differ
+// between the two scans, in the order of opts.Sort. The zero Order puts the
+// largest change first.
+func WriteDiff(w io.Writer, root *tree.DirectoryNode, oldRoot string, format string, opts Options) error {
+	if opts.Sort == (tree.Order{}) {
+		opts.Sort = tree.Order{Key: tree.SortChange}
+	}
+	root.SortChildrenRecursiveByOrder(opts.Sort, opts.Metric)
+	
+	var entries []*ChangeEntry
+	for _, node := range visibleNodes(root, opts) {
+		if node.Change == nil || node.Change.Status == tree.Unchanged {
+			continue
+		}
+		entries = append(entries, newChangeEntry(node))
+	}
+	
+	switch format {
+	case FormatJSON:
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(&DiffDocument{
+			SchemaVersion: SchemaVersion,
+			Old:           oldRoot,
+			New:           root.Path,
+			GeneratedAt:   time.Now().UTC(),
+			Changes:       entries,
+		})
+	case FormatText:
+		return writeDiffText(w, entries)
+	default:
+		return fmt.Errorf("unsupported diff format %q", format)
+	}
+}
+
+// newChangeEntry describes the change recorded on a node
+func newChangeEntry(node *tree.DirectoryNode) *ChangeEntry {
+	entry := &ChangeEntry{
+		Path:    node.RelativePath(),
+		Type:    TypeDirectory,
+		Status:  node.Change.Status.String(),
+		OldLOC:  node.Change.OldLOC,
+		NewLOC:  node.LOC,
+		Added:   node.Change.Added,
+		Removed: node.Change.Removed,
+	}
+	if node.IsFile {
+		entry.Type = TypeFile
+	}
+	return entry
+}
+
+// writeDiffText writes the changes as an aligned table
+func writeDiffText(w io.Writer, entries []*ChangeEntry) error {
+	if len(entries) == 0 {
+		_, err := fmt.Fprintln(w, "No changes")
+		return err
+	}
+	
+	table := tabwriter.NewWriter(w, 0, 0, 2, ' ', tabwriter.AlignRight)
+	fmt.Fprintln(table, "STATUS\tADDED\tREMOVED\tOLD\tNEW\t  PATH")
+	for _, entry := range entries {
+		path := entry.Path
+		if entry.Type == TypeDirectory && path != "." {
+			path += "/"
+		}
+		fmt.Fprintf(table, "%s\t+%s\t-%s\t%s\t%s\t  %s\n",
+			entry.Status,
+			format.Number(entry.Added),
+			format.Number(entry.Removed),
+			format.Number(entry.OldLOC),
+			format.Number(entry.NewLOC),
+			path)
+	}
+	return table.Flush()
+}
\ No newline at end of file
@@ -0,0 +1,70 @@
+package report
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+	
+	"github.com/user/loctree/internal/tree"
+)
+
+// changedSampleTree compares sampleTree with a copy where util.go grew
+func changedSampleTree() *tree.DirectoryNode {
+	after := sampleTree()
+	pkg := after.Children[0]
+	pkg.Files[0].FileLOC = 8
+	pkg.FileLOC = 8
+	after.CalculateLOC()
+	return tree.Diff(sampleTree(), after)
+}
+
+func TestWriteDiff_Text(t *testing.T) {
+	var buf bytes.Buffer
+	if err := WriteDiff(&buf, changedSampleTree(), "/old", FormatText, Options{MaxDepth: tree.AllDepths}); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 3 {
+		t.Fatalf("Expected a header and 2 changed directories, got:\n%s", buf.String())
+	}
+	if fields := strings.Fields(lines[2]); strings.Join(fields, " ") != "changed +3 -0 5 8 pkg/" {
+		t.Errorf("Unexpected row for pkg: %s", lines[2])
+	}
+}
+
+func TestWriteDiff_JSON(t *testing.T) {
+	var buf bytes.Buffer
+	if err := WriteDiff(&buf, changedSampleTree(), "/old", FormatJSON, Options{IncludeFiles: true, MaxDepth: tree.AllDepths}); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	
+	var doc DiffDocument
+	if err := json.Unmarshal(buf.Bytes(), &doc); err != nil {
+		t.Fatalf("Expected valid JSON, got: %v", err)
+	}
+	if doc.Old != "/old" || doc.New != "/work/project" {
+		t.Errorf("Unexpected roots: %s, %s", doc.Old, doc.New)
+	}
+	
+	// root, pkg and util.go changed; main.go did not
+	if len(doc.Changes) != 3 {
+		t.Fatalf("Expected 3 changes, got %d", len(doc.Changes))
+	}
+	util := doc.Changes[2]
+	if util.Path != "pkg/util.go" || util.Type != TypeFile || util.Added != 3 || util.NewLOC != 8 {
+		t.Errorf("Unexpected file change: %+v", util)
+	}
+}
+
+func TestWriteDiff_NoChanges(t *testing.T) {
+	var buf bytes.Buffer
+	root := tree.Diff(sampleTree(), sampleTree())
+	if err := WriteDiff(&buf, root, "/old", FormatText, Options{MaxDepth: tree.AllDepths}); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if strings.TrimSpace(buf.String()) != "No changes" {
+		t.Errorf("Expected 'No changes', got '%s'", buf.String())
+	}
+}
\ No newline at end of file
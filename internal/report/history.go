@@ -0,0 +1,117 @@
+package report
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+	
+	"github.com/user/loctree/internal/history"
+)
+
+// HistoryFormats lists the --format values supported by the history command
+var HistoryFormats = []string{FormatTUI, FormatJSON, FormatCSV}
+
+// HistoryDocument is the JSON document written by history --format json
+type HistoryDocument struct {
+	SchemaVersion int              `json:"schema_version"`
+	Root          string           `json:"root"`
+	Step          string           `json:"step"`   // daily, weekly or monthly
+	Metric        string           `json:"metric"` // Line count recorded: lines, code, comments or blanks
+	Samples       []*HistorySample `json:"samples"`
+	Series        []*HistorySeries `json:"series"` // One per directory, sorted by path
+}
+
+// HistorySample is a sampled point in time and the commit scanned for it
+type HistorySample struct {
+	Time   time.Time `json:"time"`
+	Commit string    `json:"commit"`
+}
+
+// HistorySeries holds a directory's count at every sample, in sample order
+type HistorySeries struct {
+	Path   string `json:"path"`
+	Counts []int  `json:"counts"`
+}
+
+// IsHistoryFormat reports whether name is a --format value supported by history
+func IsHistoryFormat(name string) bool {
+	for _, format := range HistoryFormats {
+		if name == format {
+			return true
+		}
+	}
+	return false
+}
+
+// WriteHistory writes the time series of every directory down to opts.MaxDepth
+func WriteHistory(w io.Writer, h *history.History, format string, opts Options) error {
+	doc := NewHistoryDocument(h, opts)
+	
+	switch format {
+	case FormatJSON:
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(doc)
+	case FormatCSV:
+		return writeHistoryCSV(w, doc)
+	default:
+		return fmt.Errorf("unsupported history format %q", format)
+	}
+}
+
+// NewHistoryDocument converts a history into a JSON document
+func NewHistoryDocument(h *history.History, opts Options) *HistoryDocument {
+	doc := &HistoryDocument{
+		SchemaVersion: SchemaVersion,
+		Root:          h.Root,
+		Step:          h.Step.String(),
+		Metric:        h.Metric.String(),
+	}
+	for _, sample := range h.Samples {
+		doc.Samples = append(doc.Samples, &HistorySample{Time: sample.Time.UTC(), Commit: sample.Commit})
+	}
+	for _, path := range h.Paths() {
+		if opts.MaxDepth >= 0 && pathDepth(path) > opts.MaxDepth {
+			continue
+		}
+		doc.Series = append(doc.Series, &HistorySeries{Path: path, Counts: h.Series(path)})
+	}
+	return doc
+}
+
+// pathDepth returns the depth of a relative path ("." is the root at depth 0)
+func pathDepth(path string) int {
+	if path == "." {
+		return 0
+	}
+	return strings.Count(path, "/") + 1
+}
+
+// writeHistoryCSV writes one row per directory and sample
+func writeHistoryCSV(w io.Writer, doc *HistoryDocument) error {
+	writer := csv.NewWriter(w)
+	if err := writer.Write([]string{"path", "time", "commit", doc.Metric}); err != nil {
+		return err
+	}
+	
+	for _, series := range doc.Series {
+		for i, sample := range doc.Samples {
+			record := []string{
+				series.Path,
+				sample.Time.Format(time.RFC3339),
+				sample.Commit,
+				strconv.Itoa(series.Counts[i]),
+			}
+			if err := writer.Write(record); err != nil {
+				return err
+			}
+		}
+	}
+	
+	writer.Flush()
+	return writer.Error()
+}
\ No newline at end of file
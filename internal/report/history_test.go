@@ -0,0 +1,70 @@
+package report
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"testing"
+	"time"
+	
+	"github.com/user/loctree/internal/history"
+	"github.com/user/loctree/internal/tree"
+)
+
+func sampleHistory() *history.History {
+	start := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	return &history.History{
+		Root:   "/work/project",
+		Step:   history.Weekly,
+		Metric: tree.MetricCode,
+		Samples: []history.Sample{
+			{Time: start, Commit: "aaa"},
+			{Time: start.AddDate(0, 0, 7), Commit: "bbb"},
+		},
+		Counts: map[string][]int{
+			".":       {10, 15},
+			"pkg":     {0, 5},
+			"pkg/sub": {0, 2},
+		},
+	}
+}
+
+func TestWriteHistory_JSON(t *testing.T) {
+	var buf bytes.Buffer
+	if err := WriteHistory(&buf, sampleHistory(), FormatJSON, Options{MaxDepth: 1}); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	
+	var doc HistoryDocument
+	if err := json.Unmarshal(buf.Bytes(), &doc); err != nil {
+		t.Fatalf("Expected valid JSON, got: %v", err)
+	}
+	if doc.Step != "weekly" || doc.Metric != "code" || len(doc.Samples) != 2 {
+		t.Errorf("Unexpected document: %+v", doc)
+	}
+	if len(doc.Series) != 2 || doc.Series[1].Path != "pkg" || doc.Series[1].Counts[1] != 5 {
+		t.Errorf("Expected series for '.' and 'pkg' only, got %+v", doc.Series)
+	}
+}
+
+func TestWriteHistory_CSV(t *testing.T) {
+	var buf bytes.Buffer
+	if err := WriteHistory(&buf, sampleHistory(), FormatCSV, Options{MaxDepth: tree.AllDepths}); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	
+	records, err := csv.NewReader(&buf).ReadAll()
+	if err != nil {
+		t.Fatalf("Expected valid CSV, got: %v", err)
+	}
+	// Header plus 3 directories at 2 samples
+	if len(records) != 7 {
+		t.Fatalf("Expected 7 rows, got %d", len(records))
+	}
+	if records[0][3] != "code" {
+		t.Errorf("Expected the count column to be named after the metric, got %v", records[0])
+	}
+	if records[2][0] != "." || records[2][2] != "bbb" || records[2][3] != "15" {
+		t.Errorf("Unexpected row: %v", records[2])
+	}
+}
\ No newline at end of file
@@ -0,0 +1,50 @@
+package report
+
+import (
+	_ "embed"
+	"html/template"
+	"io"
+	"path/filepath"
+	
+	"github.com/user/loctree/internal/tree"
+)
+
+// htmlTemplate is a self-contained page: styles, script and data are all
+// inlined so the report works offline
+//
+//go:embed templates/report.html
+var htmlTemplate string
+
+var htmlReport = template.Must(template.New("report").Parse(htmlTemplate))
+
+// htmlData is the data passed to the HTML template
+type htmlData struct {
+	Title    string
+	Metric   string    // Display name of the metric, e.g. "code"
+	Field    string    // JSON field of Node holding the metric
+	Document *Document // Embedded in the page as JSON
+}
+
+// metricFields maps each metric to the JSON field holding its total
+var metricFields = map[tree.Metric]string{
+	tree.MetricLines:    "loc",
+	tree.MetricCode:     "code",
+	tree.MetricComments: "comments",
+	tree.MetricBlanks:   "blanks",
+}
+
+// WriteHTML writes a single offline HTML page with a collapsible tree and a
+// treemap of the same data as the JSON document
+func WriteHTML(w io.Writer, root *tree.DirectoryNode, opts Options) error {
+	field, ok := metricFields[opts.Metric]
+	if !ok {
+		field = metricFields[tree.MetricLines]
+	}
+	
+	return htmlReport.Execute(w, htmlData{
+		Title:    "loctree: " + filepath.Base(root.Path),
+		Metric:   opts.Metric.String(),
+		Field:    field,
+		Document: NewDocument(root, opts),
+	})
+}
\ No newline at end of file
@@ -0,0 +1,45 @@
+package report
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+	
+	"github.com/user/loctree/internal/tree"
+)
+
+func TestWriteHTML(t *testing.T) {
+	var buf bytes.Buffer
+	if err := WriteHTML(&buf, sampleTree(), Options{IncludeFiles: true, MaxDepth: tree.AllDepths, Metric: tree.MetricCode}); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	page := buf.String()
+	
+	if !strings.Contains(page, "<title>loctree: project</title>") {
+		t.Error("Expected the page title to name the root directory")
+	}
+	if !strings.Contains(page, `var field = "code";`) {
+		t.Error("Expected the treemap to be sized by the code metric")
+	}
+	for _, external := range []string{"<link", "src=", "http://", "https://"} {
+		if strings.Contains(page, external) {
+			t.Errorf("Expected a self-contained page, found %q", external)
+		}
+	}
+	
+	// The embedded data is the JSON document
+	start := strings.Index(page, "var doc = ")
+	if start < 0 {
+		t.Fatal("Expected the document to be embedded in the page")
+	}
+	line := page[start+len("var doc = "):]
+	line = line[:strings.Index(line, ";\n")]
+	var doc Document
+	if err := json.Unmarshal([]byte(line), &doc); err != nil {
+		t.Fatalf("Expected embedded JSON, got: %v", err)
+	}
+	if doc.Tree.LOC != 15 || len(doc.Tree.Files) != 1 {
+		t.Errorf("Unexpected embedded tree: %+v", doc.Tree)
+	}
+}
\ No newline at end of file
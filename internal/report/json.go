@@ -0,0 +1,202 @@
+package report
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"time"
+	
+	"github.com/user/loctree/internal/tree"
+)
+
+// SchemaVersion is the version of the JSON document format. It is increased
+// whenever a field is removed or changes meaning; new fields may be added
+// without a version change.
+const SchemaVersion = 1
+
+// Node types used in the JSON document
+const (
+	TypeDirectory = "directory"
+	TypeFile      = "file"
+)
+
+// Document is the top-level JSON document written by --format json
+type Document struct {
+	SchemaVersion int       `json:"schema_version"`
+	Root          string    `json:"root"`         // Scanned directory as given on the command line
+	GeneratedAt   time.Time `json:"generated_at"` // When the scan finished
+	Tree          *Node     `json:"tree"`
+}
+
+// Node is a directory or file in the JSON document. Paths are relative to
+// the document root, use forward slashes, and the root itself is ".".
+type Node struct {
+	Name      string         `json:"name"`
+	Path      string         `json:"path"`
+	Type      string         `json:"type"`               // TypeDirectory or TypeFile
+	LOC       int            `json:"loc"`                // Total lines (including children)
+	FileLOC   int            `json:"file_loc"`           // Lines from files directly in this directory
+	Code      int            `json:"code"`               // Total code lines
+	Comments  int            `json:"comments"`           // Total comment lines
+	Blanks    int            `json:"blanks"`             // Total blank lines
+	FileCount int            `json:"file_count"`         // Total number of files
+	Language  string         `json:"language,omitempty"` // Detected language (files only)
+	Size      int64          `json:"size,omitempty"`     // Size in bytes (files only)
+	Languages map[string]int `json:"languages,omitempty"`
+	Children  []*Node        `json:"children,omitempty"` // Subdirectories
+	Files     []*Node        `json:"files,omitempty"`    // Files, when included
+}
+
+// NewDocument converts a built tree into a JSON document
+func NewDocument(root *tree.DirectoryNode, opts Options) *Document {
+	return &Document{
+		SchemaVersion: SchemaVersion,
+		Root:          root.Path,
+		GeneratedAt:   time.Now().UTC(),
+		Tree:          newNode(root, ".", 0, opts),
+	}
+}
+
+// newNode converts a tree node and its descendants down to opts.MaxDepth
+func newNode(n *tree.DirectoryNode, path string, depth int, opts Options) *Node {
+	node := &Node{
+		Name:      n.Name,
+		Path:      path,
+		Type:      TypeDirectory,
+		LOC:       n.LOC,
+		FileLOC:   n.FileLOC,
+		Code:      n.Code,
+		Comments:  n.Comments,
+		Blanks:    n.Blanks,
+		FileCount: n.FileCount,
+		Languages: n.Languages,
+	}
+	if n.IsFile {
+		node.Type = TypeFile
+		node.Language = n.Language
+		node.Size = n.Size
+		node.Languages = nil
+	}
+	
+	if opts.MaxDepth >= 0 && depth >= opts.MaxDepth {
+		return node
+	}
+	
+	for _, child := range n.Children {
+		node.Children = append(node.Children, newNode(child, childPath(path, child.Name), depth+1, opts))
+	}
+	if opts.IncludeFiles {
+		for _, file := range n.Files {
+			node.Files = append(node.Files, newNode(file, childPath(path, file.Name), depth+1, opts))
+		}
+	}
+	
+	return node
+}
+
+// childPath joins a relative slash path and a name, treating "." as the root
+func childPath(parent, name string) string {
+	if parent == "." {
+		return name
+	}
+	return filepath.ToSlash(filepath.Join(parent, name))
+}
+
+// WriteJSON writes the tree as an indented JSON document
+func WriteJSON(w io.Writer, root *tree.DirectoryNode, opts Options) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(NewDocument(root, opts))
+}
+
+// ReadJSON reads a document written by WriteJSON
+func ReadJSON(r io.Reader) (*Document, error) {
+	var doc Document
+	if err := json.NewDecoder(r).Decode(&doc); err != nil {
+		return nil, err
+	}
+	if doc.SchemaVersion < 1 || doc.SchemaVersion > SchemaVersion {
+		return nil, fmt.Errorf("unsupported schema version %d (expected %d)", doc.SchemaVersion, SchemaVersion)
+	}
+	if doc.Tree == nil {
+		return nil, fmt.Errorf("document has no tree")
+	}
+	return &doc, nil
+}
+
+// LoadSnapshot reads a JSON document from a file and rebuilds its tree
+func LoadSnapshot(path string) (*tree.DirectoryNode, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+	
+	doc, err := ReadJSON(file)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %v", path, err)
+	}
+	return doc.ToTree(), nil
+}
+
+// ToTree rebuilds the tree recorded in the document, with node paths
+// resolved against the document root
+func (d *Document) ToTree() *tree.DirectoryNode {
+	return d.Tree.toTree(d.Root)
+}
+
+// toTree converts a document node and its descendants back into tree nodes.
+// Counts for files directly in a directory are its totals less those of its
+// subdirectories, mirroring CalculateLOC.
+func (node *Node) toTree(path string) *tree.DirectoryNode {
+	var n *tree.DirectoryNode
+	if node.Type == TypeFile {
+		n = tree.NewFileNode(node.Name, path)
+	} else {
+		n = tree.NewDirectoryNode(node.Name, path)
+	}
+	
+	n.Language = node.Language
+	n.Size = node.Size
+	n.LOC = node.LOC
+	n.FileLOC = node.FileLOC
+	n.Code = node.Code
+	n.Comments = node.Comments
+	n.Blanks = node.Blanks
+	n.FileCount = node.FileCount
+	
+	if n.IsFile {
+		n.FileCode, n.FileComments, n.FileBlanks = n.Code, n.Comments, n.Blanks
+		if n.Language != "" {
+			n.Languages[n.Language] = n.LOC
+			n.FileLanguages[n.Language] = n.LOC
+		}
+		return n
+	}
+	
+	for lang, loc := range node.Languages {
+		n.Languages[lang] = loc
+		n.FileLanguages[lang] = loc
+	}
+	n.FileCode, n.FileComments, n.FileBlanks = n.Code, n.Comments, n.Blanks
+	for _, child := range node.Children {
+		c := child.toTree(filepath.Join(path, child.Name))
+		n.AddChild(c)
+		n.FileCode -= c.Code
+		n.FileComments -= c.Comments
+		n.FileBlanks -= c.Blanks
+		for lang, loc := range c.Languages {
+			n.FileLanguages[lang] -= loc
+			if n.FileLanguages[lang] <= 0 {
+				delete(n.FileLanguages, lang)
+			}
+		}
+	}
+	for _, file := range node.Files {
+		n.AddFile(file.toTree(filepath.Join(path, file.Name)))
+	}
+	
+	return n
+}
\ No newline at end of file
@@ -0,0 +1,160 @@
+package report
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+	
+	"github.com/user/loctree/internal/scanner"
+	"github.com/user/loctree/internal/tree"
+)
+
+// sampleTree builds root (main.go) -> pkg (util.go)
+func sampleTree() *tree.DirectoryNode {
+	root := tree.NewDirectoryNode("project", "/work/project")
+	pkg := tree.NewDirectoryNode("pkg", "/work/project/pkg")
+	root.AddChild(pkg)
+	
+	mainFile := root.AddCountedFile("main.go", scanner.FileStats{
+		Language:   "Go",
+		LineCounts: scanner.LineCounts{Lines: 10, Code: 8, Blanks: 2},
+	})
+	mainFile.Size = 120
+	pkg.AddCountedFile("util.go", scanner.FileStats{
+		Language:   "Go",
+		LineCounts: scanner.LineCounts{Lines: 5, Code: 4, Comments: 1},
+	})
+	
+	root.CalculateLOC()
+	return root
+}
+
+func TestNewDocument(t *testing.T) {
+	doc := NewDocument(sampleTree(), Options{MaxDepth: tree.AllDepths})
+	
+	if doc.SchemaVersion != SchemaVersion {
+		t.Errorf("Expected schema version %d, got %d", SchemaVersion, doc.SchemaVersion)
+	}
+	if doc.Root != "/work/project" {
+		t.Errorf("Expected root '/work/project', got '%s'", doc.Root)
+	}
+	
+	root := doc.Tree
+	if root.Path != "." || root.Type != TypeDirectory {
+		t.Errorf("Expected root directory at '.', got %s at '%s'", root.Type, root.Path)
+	}
+	if root.LOC != 15 || root.FileLOC != 10 || root.Code != 12 || root.Comments != 1 || root.Blanks != 2 {
+		t.Errorf("Unexpected root counts: %+v", root)
+	}
+	if root.Languages["Go"] != 15 {
+		t.Errorf("Expected 15 Go lines, got %d", root.Languages["Go"])
+	}
+	if len(root.Children) != 1 || root.Children[0].Path != "pkg" {
+		t.Fatalf("Expected child directory 'pkg', got %+v", root.Children)
+	}
+	if len(root.Files) != 0 {
+		t.Error("Expected files to be omitted by default")
+	}
+}
+
+func TestNewDocument_IncludeFiles(t *testing.T) {
+	doc := NewDocument(sampleTree(), Options{IncludeFiles: true, MaxDepth: tree.AllDepths})
+	
+	if len(doc.Tree.Files) != 1 {
+		t.Fatalf("Expected 1 file in root, got %d", len(doc.Tree.Files))
+	}
+	file := doc.Tree.Files[0]
+	if file.Type != TypeFile || file.Path != "main.go" || file.Language != "Go" || file.Size != 120 || file.LOC != 10 {
+		t.Errorf("Unexpected file node: %+v", file)
+	}
+	
+	util := doc.Tree.Children[0].Files[0]
+	if util.Path != "pkg/util.go" {
+		t.Errorf("Expected nested file path 'pkg/util.go', got '%s'", util.Path)
+	}
+}
+
+func TestWriteJSON_RoundTrip(t *testing.T) {
+	var buf bytes.Buffer
+	if err := WriteJSON(&buf, sampleTree(), Options{IncludeFiles: true, MaxDepth: tree.AllDepths}); err != nil {
+		t.Fatalf("Error writing JSON: %v", err)
+	}
+	
+	var doc Document
+	if err := json.Unmarshal(buf.Bytes(), &doc); err != nil {
+		t.Fatalf("Error unmarshalling JSON: %v", err)
+	}
+	
+	if doc.SchemaVersion != SchemaVersion {
+		t.Errorf("Expected schema version %d, got %d", SchemaVersion, doc.SchemaVersion)
+	}
+	if doc.Tree.LOC != 15 || doc.Tree.Children[0].LOC != 5 {
+		t.Errorf("Unexpected LOC after round trip: %d, %d", doc.Tree.LOC, doc.Tree.Children[0].LOC)
+	}
+	
+	// Field names are part of the schema
+	for _, field := range []string{`"schema_version"`, `"file_loc"`, `"children"`, `"languages"`} {
+		if !bytes.Contains(buf.Bytes(), []byte(field)) {
+			t.Errorf("Expected field %s in JSON output", field)
+		}
+	}
+}
+
+func TestWrite_UnknownFormat(t *testing.T) {
+	var buf bytes.Buffer
+	if err := Write(&buf, sampleTree(), "yaml", Options{}); err == nil {
+		t.Error("Expected error for unsupported format")
+	}
+}
+
+func TestNewDocument_MaxDepth(t *testing.T) {
+	doc := NewDocument(sampleTree(), Options{IncludeFiles: true, MaxDepth: 1})
+	
+	if len(doc.Tree.Children) != 1 || len(doc.Tree.Files) != 1 {
+		t.Fatalf("Expected the root's children and files, got %+v", doc.Tree)
+	}
+	pkg := doc.Tree.Children[0]
+	if len(pkg.Files) != 0 {
+		t.Error("Expected nodes below the depth limit to be omitted")
+	}
+	if pkg.LOC != 5 {
+		t.Errorf("Expected truncated node to keep its total of 5, got %d", pkg.LOC)
+	}
+}
+
+func TestReadJSON_ToTree(t *testing.T) {
+	var buf bytes.Buffer
+	if err := WriteJSON(&buf, sampleTree(), Options{IncludeFiles: true, MaxDepth: tree.AllDepths}); err != nil {
+		t.Fatalf("Error writing JSON: %v", err)
+	}
+	
+	doc, err := ReadJSON(&buf)
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	root := doc.ToTree()
+	
+	if root.Path != "/work/project" || root.LOC != 15 || root.FileLOC != 10 {
+		t.Errorf("Unexpected root: %s with %d lines (%d own)", root.Path, root.LOC, root.FileLOC)
+	}
+	if root.FileCode != 8 || root.FileLanguages["Go"] != 10 {
+		t.Errorf("Expected own counts to exclude children, got %d code, %d Go", root.FileCode, root.FileLanguages["Go"])
+	}
+	
+	pkg := root.Children[0]
+	if pkg.Parent != root || pkg.Path != "/work/project/pkg" || len(pkg.Files) != 1 {
+		t.Fatalf("Unexpected pkg: %+v", pkg)
+	}
+	util := pkg.Files[0]
+	if !util.IsFile || util.LOC != 5 || util.Language != "Go" {
+		t.Errorf("Unexpected file: %+v", util)
+	}
+}
+
+func TestReadJSON_UnsupportedVersion(t *testing.T) {
+	_, err := ReadJSON(strings.NewReader(`{"schema_version": 99, "tree": {}}`))
+	if err == nil {
+		t.Error("Expected error for unsupported schema version")
+	}
+}
\ No newline at end of file
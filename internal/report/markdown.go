@@ -0,0 +1,37 @@
+package report
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	
+	"github.com/user/loctree/internal/format"
+	"github.com/user/loctree/internal/tree"
+)
+
+// WriteMarkdown writes the tree as a nested Markdown list, suitable for PR
+// descriptions, e.g. "- `internal/` — 1,234 lines (Go 1,200 · YAML 34)"
+func WriteMarkdown(w io.Writer, root *tree.DirectoryNode, opts Options) error {
+	for _, node := range visibleNodes(root, opts) {
+		indent := strings.Repeat("  ", node.Depth())
+		count := fmt.Sprintf("%s %s", format.Number(node.Count(opts.Metric)), opts.Metric)
+		
+		var line string
+		if node.IsFile {
+			line = fmt.Sprintf("%s- `%s` — %s %s", indent, node.Name, count, format.FileDetails(node))
+		} else {
+			line = fmt.Sprintf("%s- **`%s/`** — %s", indent, node.Name, count)
+			if languages := format.Languages(node.LanguageBreakdown(), maxMarkdownLanguages); languages != "" {
+				line += fmt.Sprintf(" (%s)", languages)
+			}
+		}
+		
+		if _, err := fmt.Fprintln(w, line); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// maxMarkdownLanguages limits how many languages each Markdown list item names
+const maxMarkdownLanguages = 3
\ No newline at end of file
@@ -0,0 +1,23 @@
+package report
+
+import (
+	"bytes"
+	"testing"
+	
+	"github.com/user/loctree/internal/tree"
+)
+
+func TestWriteMarkdown(t *testing.T) {
+	var buf bytes.Buffer
+	if err := WriteMarkdown(&buf, sampleTree(), Options{IncludeFiles: true, MaxDepth: tree.AllDepths}); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	
+	expected := "- **`project/`** — 15 lines (Go 15)\n" +
+		"  - **`pkg/`** — 5 lines (Go 5)\n" +
+		"    - `util.go` — 5 lines (Go, 0 B)\n" +
+		"  - `main.go` — 10 lines (Go, 120 B)\n"
+	if buf.String() != expected {
+		t.Errorf("Expected:\n%s\ngot:\n%s", expected, buf.String())
+	}
+}
\ No newline at end of file
@@ -0,0 +1,68 @@
+package report
+
+import (
+	"fmt"
+	"io"
+	
+	"github.com/user/loctree/internal/tree"
+)
+
+// Output formats. FormatTUI launches the interactive viewer; the others
+// write a report to stdout and exit.
+const (
+	FormatTUI      = "tui"
+	FormatJSON     = "json"
+	FormatText     = "text"
+	FormatMarkdown = "markdown"
+	FormatCSV      = "csv"
+	FormatHTML     = "html"
+)
+
+// Formats lists every supported --format value
+var Formats = []string{FormatTUI, FormatJSON, FormatText, FormatMarkdown, FormatCSV, FormatHTML}
+
+// Options controls which parts of the tree are reported and how
+type Options struct {
+	IncludeFiles bool        // Report files as leaves of their directory
+	MaxDepth     int         // Deepest level reported (the root is 0), or tree.AllDepths
+	Metric       tree.Metric // Line count reported and used for sorting
+	Sort         tree.Order  // Order of siblings; the zero Order sorts by the metric, or in diffs by the change, largest first
+}
+
+// IsFormat reports whether name is a supported --format value
+func IsFormat(name string) bool {
+	for _, format := range Formats {
+		if name == format {
+			return true
+		}
+	}
+	return false
+}
+
+// Write writes the tree to w in the given non-interactive format, sorted and
+// limited to the depth in opts
+func Write(w io.Writer, root *tree.DirectoryNode, format string, opts Options) error {
+	root.SortChildrenRecursiveByOrder(opts.Sort, opts.Metric)
+	
+	switch format {
+	case FormatJSON:
+		return WriteJSON(w, root, opts)
+	case FormatText:
+		return WriteText(w, root, opts)
+	case FormatMarkdown:
+		return WriteMarkdown(w, root, opts)
+	case FormatCSV:
+		return WriteCSV(w, root, opts)
+	case FormatHTML:
+		return WriteHTML(w, root, opts)
+	default:
+		return fmt.Errorf("unsupported report format %q", format)
+	}
+}
+
+// visibleNodes returns the rows the TUI would show with the tree expanded
+// down to opts.MaxDepth. The tree's expanded state is changed accordingly.
+func visibleNodes(root *tree.DirectoryNode, opts Options) []*tree.DirectoryNode {
+	tree.ExpandToDepth(root, opts.MaxDepth)
+	return tree.GetVisibleNodesWithFiles(root, opts.IncludeFiles)
+}
\ No newline at end of file
@@ -0,0 +1,21 @@
+package report
+
+import (
+	"fmt"
+	"io"
+	
+	"github.com/user/loctree/internal/format"
+	"github.com/user/loctree/internal/tree"
+)
+
+// WriteText writes an indented tree without styling, one row per node,
+// exactly as the TUI shows it when expanded to opts.MaxDepth
+func WriteText(w io.Writer, root *tree.DirectoryNode, opts Options) error {
+	for _, node := range visibleNodes(root, opts) {
+		row := format.TreeRow(node, node.Depth(), opts.Metric, opts.IncludeFiles)
+		if _, err := fmt.Fprintln(w, row); err != nil {
+			return err
+		}
+	}
+	return nil
+}
\ No newline at end of file
@@ -0,0 +1,71 @@
+package report
+
+import (
+	"bytes"
+	"testing"
+	
+	"github.com/user/loctree/internal/tree"
+)
+
+func TestWriteText(t *testing.T) {
+	var buf bytes.Buffer
+	if err := WriteText(&buf, sampleTree(), Options{IncludeFiles: true, MaxDepth: tree.AllDepths}); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	
+	expected := "▼ 15 project\n" +
+		"  ▼ 5 pkg\n" +
+		"    5 util.go (Go, 0 B)\n" +
+		"  10 main.go (Go, 120 B)\n"
+	if buf.String() != expected {
+		t.Errorf("Expected:\n%s\ngot:\n%s", expected, buf.String())
+	}
+}
+
+func TestWriteText_DepthAndMetric(t *testing.T) {
+	var buf bytes.Buffer
+	opts := Options{MaxDepth: 1, Metric: tree.MetricCode}
+	if err := Write(&buf, sampleTree(), FormatText, opts); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	
+	expected := "▼ 12 project\n" +
+		"  4 pkg\n"
+	if buf.String() != expected {
+		t.Errorf("Expected:\n%s\ngot:\n%s", expected, buf.String())
+	}
+}
+
+func TestWriteText_DepthZero(t *testing.T) {
+	var buf bytes.Buffer
+	if err := Write(&buf, sampleTree(), FormatText, Options{}); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	
+	// Only the root, like the collapsed TUI
+	expected := "▶ 15 project\n"
+	if buf.String() != expected {
+		t.Errorf("Expected:\n%s\ngot:\n%s", expected, buf.String())
+	}
+}
+
+func TestWrite_Sort(t *testing.T) {
+	root := sampleTree()
+	docs := tree.NewDirectoryNode("docs", "/work/project/docs")
+	docs.FileLOC = 7
+	root.AddChild(docs)
+	root.CalculateLOC()
+	
+	var buf bytes.Buffer
+	opts := Options{MaxDepth: 1, Sort: tree.Order{Key: tree.SortLOC, Reverse: true}}
+	if err := Write(&buf, root, FormatText, opts); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	
+	expected := "▼ 22 project\n" +
+		"  5 pkg\n" +
+		"  7 docs\n"
+	if buf.String() != expected {
+		t.Errorf("Expected:\n%s\ngot:\n%s", expected, buf.String())
+	}
+}
\ No newline at end of file
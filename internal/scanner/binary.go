@@ -19,12 +19,15 @@ func IsBinary(filePath string) (bool, error) {
 		return false, nil // Treat read errors as non-binary
 	}
 	
-	// Check for null bytes
-	for i := 0; i < n; i++ {
-		if buffer[i] == 0 {
-			return true, nil
+	return isBinaryData(buffer[:n]), nil
+}
+
+// isBinaryData reports whether the start of a file contains null bytes
+func isBinaryData(data []byte) bool {
+	for _, b := range data {
+		if b == 0 {
+			return true
 		}
 	}
-	
-	return false, nil
+	return false
 }
\ No newline at end of file
@@ -2,7 +2,12 @@ package scanner
 
 import (
 	"bufio"
+	"io"
+	"io/fs"
 	"os"
+	"path"
+	"path/filepath"
+	"strings"
 )
 
 // CountLines counts the number of lines in a file
@@ -34,4 +39,262 @@ func CountLines(filePath string) (int, error) {
 	}
 	
 	return lines, nil
+}
+
+// LineCounts holds the number of lines in a file split by kind
+type LineCounts struct {
+	Lines    int // All lines (Code + Comments + Blanks)
+	Code     int
+	Comments int
+	Blanks   int
+}
+
+// FileStats holds the detected language and classified line counts of a file
+type FileStats struct {
+	Language string
+	LineCounts
+}
+
+// CountFile detects the language of a file and classifies each of its lines
+// as code, comment or blank. Returns zero counts for binary files.
+func CountFile(filePath string) (FileStats, error) {
+	file, err := os.Open(filePath)
+	if err != nil {
+		return FileStats{}, err
+	}
+	defer file.Close()
+	
+	return CountReader(file, filepath.Base(filePath))
+}
+
+// CountFileFS is like CountFile for a file in fsys, named by a slash-separated path
+func CountFileFS(fsys fs.FS, name string) (FileStats, error) {
+	file, err := fsys.Open(name)
+	if err != nil {
+		return FileStats{}, err
+	}
+	defer file.Close()
+	
+	return CountReader(file, path.Base(name))
+}
+
+// CountReader classifies the lines of a file read from r. The file name is
+// used to detect the language, falling back to a shebang line. Returns zero
+// counts for binary content.
+func CountReader(r io.Reader, name string) (FileStats, error) {
+	reader := bufio.NewReader(r)
+	
+	// Binary files contain null bytes near the start
+	head, _ := reader.Peek(512)
+	if isBinaryData(head) {
+		return FileStats{}, nil
+	}
+	
+	// Detect the language, falling back to the shebang line
+	language := LanguageFromName(name)
+	if language == "" {
+		firstLine := head
+		if len(firstLine) > 256 {
+			firstLine = firstLine[:256]
+		}
+		line, _, _ := strings.Cut(string(firstLine), "\n")
+		language = LanguageFromShebang(line)
+	}
+	if language == "" {
+		language = UnknownLanguage
+	}
+	
+	counts, err := ClassifyLines(reader, language)
+	if err != nil {
+		return FileStats{}, err
+	}
+	
+	return FileStats{Language: language, LineCounts: counts}, nil
+}
+
+// ClassifyLines reads r and classifies each line as code, comment or blank
+// using the comment syntax of the given language
+func ClassifyLines(r io.Reader, language string) (LineCounts, error) {
+	syntax := commentSyntaxByLanguage[language]
+	classifier := newLineClassifier(syntax)
+	reader := bufio.NewReader(r)
+	counts := LineCounts{}
+	
+	for {
+		line, err := reader.ReadString('\n')
+		if len(line) > 0 {
+			counts.Lines++
+			switch classifier.classify(strings.TrimRight(line, "\r\n")) {
+			case lineCode:
+				counts.Code++
+			case lineComment:
+				counts.Comments++
+			default:
+				counts.Blanks++
+			}
+		}
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return LineCounts{}, err
+		}
+	}
+	
+	return counts, nil
+}
+
+type lineKind int
+
+const (
+	lineBlank lineKind = iota
+	lineCode
+	lineComment
+)
+
+// lineClassifier tracks block comment and string state across lines
+type lineClassifier struct {
+	syntax     commentSyntax
+	depth      int       // Nesting depth of the current block comment
+	blockEnd   string    // End delimiter of the current block comment
+	blockStart string    // Start delimiter of the current block comment
+	quote      string    // Delimiter of the current string literal
+	raw        bool      // Whether the current string literal ignores escapes
+	special    [256]bool // Bytes that may start a comment or string literal
+}
+
+// newLineClassifier creates a classifier for the given comment syntax
+func newLineClassifier(syntax commentSyntax) *lineClassifier {
+	c := &lineClassifier{syntax: syntax}
+	markers := append(append([]string{}, syntax.lineComments...), syntax.quotes...)
+	markers = append(markers, syntax.rawQuotes...)
+	for _, block := range syntax.blockComments {
+		markers = append(markers, block.start)
+	}
+	for _, marker := range markers {
+		c.special[marker[0]] = true
+	}
+	return c
+}
+
+// classify determines the kind of a single line and updates the state
+func (c *lineClassifier) classify(line string) lineKind {
+	if strings.TrimSpace(line) == "" && c.quote == "" {
+		return lineBlank
+	}
+	
+	// A line that continues a multi-line string literal is code
+	hasCode := c.quote != ""
+	hasComment := false
+	
+	for i := 0; i < len(line); {
+		rest := line[i:]
+		
+		switch {
+		case c.quote != "":
+			// Inside a string literal: everything is code until it closes
+			hasCode = true
+			if !c.raw && rest[0] == '\\' {
+				i += 2
+				continue
+			}
+			if strings.HasPrefix(rest, c.quote) {
+				i += len(c.quote)
+				c.quote = ""
+				continue
+			}
+			i++
+			
+		case c.depth > 0:
+			// Inside a block comment: look for nested starts and the end
+			hasComment = true
+			if c.syntax.nested && strings.HasPrefix(rest, c.blockStart) {
+				c.depth++
+				i += len(c.blockStart)
+				continue
+			}
+			if strings.HasPrefix(rest, c.blockEnd) {
+				c.depth--
+				i += len(c.blockEnd)
+				continue
+			}
+			i++
+			
+		case rest[0] == ' ' || rest[0] == '\t':
+			i++
+			
+		case !c.special[rest[0]]:
+			hasCode = true
+			i++
+			
+		default:
+			// Block comments first: in Lua, Julia and Nim their start
+			// begins with the line comment marker
+			if block, ok := c.blockCommentAt(rest); ok {
+				hasComment = true
+				c.depth = 1
+				c.blockStart = block.start
+				c.blockEnd = block.end
+				i += len(block.start)
+				continue
+			}
+			if hasPrefixAny(rest, c.syntax.lineComments) != "" {
+				return c.finish(hasCode, true)
+			}
+			hasCode = true
+			if quote := hasPrefixAny(rest, c.syntax.rawQuotes); quote != "" {
+				c.quote = quote
+				c.raw = true
+				i += len(quote)
+				continue
+			}
+			if quote := hasPrefixAny(rest, c.syntax.quotes); quote != "" {
+				c.quote = quote
+				c.raw = false
+				i += len(quote)
+				continue
+			}
+			i++
+		}
+	}
+	
+	// Single-character quoted strings do not span lines unless escaped
+	if c.quote == `"` || c.quote == `'` {
+		if !strings.HasSuffix(line, "\\") {
+			c.quote = ""
+		}
+	}
+	
+	return c.finish(hasCode, hasComment)
+}
+
+// finish picks the line kind: any code makes it a code line
+func (c *lineClassifier) finish(hasCode, hasComment bool) lineKind {
+	if hasCode {
+		return lineCode
+	}
+	if hasComment {
+		return lineComment
+	}
+	return lineBlank
+}
+
+// blockCommentAt returns the block comment starting at the beginning of s
+func (c *lineClassifier) blockCommentAt(s string) (blockComment, bool) {
+	for _, block := range c.syntax.blockComments {
+		if strings.HasPrefix(s, block.start) {
+			return block, true
+		}
+	}
+	return blockComment{}, false
+}
+
+// hasPrefixAny returns the first of the prefixes that s starts with
+func hasPrefixAny(s string, prefixes []string) string {
+	for _, prefix := range prefixes {
+		if strings.HasPrefix(s, prefix) {
+			return prefix
+		}
+	}
+	return ""
 }
\ No newline at end of file
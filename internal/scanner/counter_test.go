@@ -2,6 +2,7 @@ package scanner
 
 import (
 	"path/filepath"
+	"strings"
 	"testing"
 )
 
@@ -58,4 +59,161 @@ func TestCountLines_NonExistentFile(t *testing.T) {
 	if count != 0 {
 		t.Errorf("Expected 0 lines for non-existent file, got %d", count)
 	}
+}
+
+func classify(t *testing.T, source, language string) LineCounts {
+	t.Helper()
+	counts, err := ClassifyLines(strings.NewReader(source), language)
+	if err != nil {
+		t.Fatalf("Error classifying lines: %v", err)
+	}
+	return counts
+}
+
+func TestClassifyLines_GoComments(t *testing.T) {
+	source := `// Package main is an example
+package main
+
+/*
+Block comment
+
+spanning lines
+*/
+func main() { // trailing comment is still code
+	println("Hello") /* inline */
+}
+`
+	counts := classify(t, source, "Go")
+	
+	expected := LineCounts{Lines: 11, Code: 4, Comments: 5, Blanks: 2}
+	if counts != expected {
+		t.Errorf("Expected %+v, got %+v", expected, counts)
+	}
+}
+
+func TestClassifyLines_StringsContainingCommentMarkers(t *testing.T) {
+	source := `url := "http://example.com"
+pattern := "/* not a comment"
+raw := ` + "`" + `
+// still inside a raw string
+` + "`" + `
+`
+	counts := classify(t, source, "Go")
+	
+	if counts.Code != 5 || counts.Comments != 0 {
+		t.Errorf("Expected 5 code and 0 comment lines, got %+v", counts)
+	}
+}
+
+func TestClassifyLines_NestedBlockComments(t *testing.T) {
+	source := `/* outer
+/* inner */
+still a comment
+*/
+fn main() {}
+`
+	counts := classify(t, source, "Rust")
+	
+	if counts.Comments != 4 || counts.Code != 1 {
+		t.Errorf("Expected 4 comment and 1 code lines, got %+v", counts)
+	}
+}
+
+func TestClassifyLines_UnnestedBlockComments(t *testing.T) {
+	// In C the first */ closes the comment even after a second /*
+	source := `/* outer
+/* inner */
+int x = 1;
+`
+	counts := classify(t, source, "C")
+	
+	if counts.Comments != 2 || counts.Code != 1 {
+		t.Errorf("Expected 2 comment and 1 code lines, got %+v", counts)
+	}
+}
+
+func TestClassifyLines_HashComments(t *testing.T) {
+	source := `#!/usr/bin/env python3
+# A comment
+
+def main():
+    print("# not a comment")
+`
+	counts := classify(t, source, "Python")
+	
+	expected := LineCounts{Lines: 5, Code: 2, Comments: 2, Blanks: 1}
+	if counts != expected {
+		t.Errorf("Expected %+v, got %+v", expected, counts)
+	}
+}
+
+func TestClassifyLines_BlockCommentsStartingWithLineComment(t *testing.T) {
+	tests := []struct {
+		language string
+		source   string
+	}{
+		{"Lua", "--[[\nA block\ncomment\n]]\n-- a line comment\nlocal x = 1\n"},
+		{"Julia", "#=\nA block\ncomment\n=#\n# a line comment\nx = 1\n"},
+		{"Nim", "#[\nA block\ncomment\n]#\n# a line comment\nlet x = 1\n"},
+	}
+	
+	for _, tt := range tests {
+		counts := classify(t, tt.source, tt.language)
+		expected := LineCounts{Lines: 6, Code: 1, Comments: 5}
+		if counts != expected {
+			t.Errorf("%s: expected %+v, got %+v", tt.language, expected, counts)
+		}
+	}
+}
+
+func TestClassifyLines_NoCommentSyntax(t *testing.T) {
+	counts := classify(t, "one\n\ntwo", "Text")
+	
+	expected := LineCounts{Lines: 3, Code: 2, Comments: 0, Blanks: 1}
+	if counts != expected {
+		t.Errorf("Expected %+v, got %+v", expected, counts)
+	}
+}
+
+func TestCountFile(t *testing.T) {
+	mainFile := filepath.Join("testdata", "test_project", "main.go")
+	stats, err := CountFile(mainFile)
+	if err != nil {
+		t.Fatalf("Error counting file: %v", err)
+	}
+	
+	if stats.Language != "Go" {
+		t.Errorf("Expected language 'Go', got '%s'", stats.Language)
+	}
+	expected := LineCounts{Lines: 5, Code: 4, Comments: 0, Blanks: 1}
+	if stats.LineCounts != expected {
+		t.Errorf("Expected %+v, got %+v", expected, stats.LineCounts)
+	}
+}
+
+func TestCountFile_BinaryFile(t *testing.T) {
+	binaryFile := filepath.Join("testdata", "binary_file.bin")
+	stats, err := CountFile(binaryFile)
+	if err != nil {
+		t.Fatalf("Error counting file: %v", err)
+	}
+	if stats.Lines != 0 {
+		t.Errorf("Expected 0 lines for binary file, got %d", stats.Lines)
+	}
+}
+
+func TestCountReader(t *testing.T) {
+	stats, err := CountReader(strings.NewReader("#!/usr/bin/env python3\n# comment\nprint('hi')\n"), "run")
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	// The shebang line is a comment in Python
+	if stats.Language != "Python" || stats.Code != 1 || stats.Comments != 2 {
+		t.Errorf("Expected 1 Python code line and 2 comments, got %+v", stats)
+	}
+	
+	stats, err = CountReader(strings.NewReader("bin\x00ary\n"), "data.go")
+	if err != nil || stats.Lines != 0 {
+		t.Errorf("Expected zero counts for binary content, got %+v (%v)", stats, err)
+	}
 }
\ No newline at end of file
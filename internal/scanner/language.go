@@ -0,0 +1,353 @@
+package scanner
+
+import (
+	"path/filepath"
+	"strings"
+)
+
+// UnknownLanguage is reported for text files whose language cannot be detected
+const UnknownLanguage = "Other"
+
+// languagesByFilename maps well-known file names to their language
+var languagesByFilename = map[string]string{
+	"makefile":       "Makefile",
+	"gnumakefile":    "Makefile",
+	"dockerfile":     "Dockerfile",
+	"containerfile":  "Dockerfile",
+	"cmakelists.txt": "CMake",
+	"rakefile":       "Ruby",
+	"gemfile":        "Ruby",
+	"podfile":        "Ruby",
+	"vagrantfile":    "Ruby",
+	"jenkinsfile":    "Groovy",
+	"justfile":       "Just",
+	"build.bazel":    "Starlark",
+	"go.mod":         "Go Module",
+	"go.sum":         "Go Module",
+}
+
+// languagesByExtension maps lower-case file extensions to their language
+var languagesByExtension = map[string]string{
+	".go":         "Go",
+	".c":          "C",
+	".h":          "C",
+	".cc":         "C++",
+	".cpp":        "C++",
+	".cxx":        "C++",
+	".hh":         "C++",
+	".hpp":        "C++",
+	".hxx":        "C++",
+	".cs":         "C#",
+	".java":       "Java",
+	".kt":         "Kotlin",
+	".kts":        "Kotlin",
+	".scala":      "Scala",
+	".groovy":     "Groovy",
+	".gradle":     "Groovy",
+	".rs":         "Rust",
+	".swift":      "Swift",
+	".m":          "Objective-C",
+	".mm":         "Objective-C++",
+	".py":         "Python",
+	".pyi":        "Python",
+	".rb":         "Ruby",
+	".php":        "PHP",
+	".pl":         "Perl",
+	".pm":         "Perl",
+	".lua":        "Lua",
+	".r":          "R",
+	".jl":         "Julia",
+	".dart":       "Dart",
+	".ex":         "Elixir",
+	".exs":        "Elixir",
+	".erl":        "Erlang",
+	".hrl":        "Erlang",
+	".hs":         "Haskell",
+	".ml":         "OCaml",
+	".mli":        "OCaml",
+	".fs":         "F#",
+	".fsx":        "F#",
+	".clj":        "Clojure",
+	".cljs":       "Clojure",
+	".zig":        "Zig",
+	".nim":        "Nim",
+	".js":         "JavaScript",
+	".mjs":        "JavaScript",
+	".cjs":        "JavaScript",
+	".jsx":        "JavaScript",
+	".ts":         "TypeScript",
+	".mts":        "TypeScript",
+	".cts":        "TypeScript",
+	".tsx":        "TypeScript",
+	".vue":        "Vue",
+	".svelte":     "Svelte",
+	".html":       "HTML",
+	".htm":        "HTML",
+	".css":        "CSS",
+	".scss":       "SCSS",
+	".sass":       "Sass",
+	".less":       "Less",
+	".sh":         "Shell",
+	".bash":       "Shell",
+	".zsh":        "Shell",
+	".fish":       "Fish",
+	".ps1":        "PowerShell",
+	".bat":        "Batch",
+	".cmd":        "Batch",
+	".sql":        "SQL",
+	".proto":      "Protocol Buffers",
+	".graphql":    "GraphQL",
+	".gql":        "GraphQL",
+	".tf":         "Terraform",
+	".hcl":        "HCL",
+	".json":       "JSON",
+	".yaml":       "YAML",
+	".yml":        "YAML",
+	".toml":       "TOML",
+	".xml":        "XML",
+	".ini":        "INI",
+	".cfg":        "INI",
+	".md":         "Markdown",
+	".markdown":   "Markdown",
+	".rst":        "reStructuredText",
+	".adoc":       "AsciiDoc",
+	".tex":        "TeX",
+	".txt":        "Text",
+	".csv":        "CSV",
+	".mk":         "Makefile",
+	".cmake":      "CMake",
+	".dockerfile": "Dockerfile",
+	".bzl":        "Starlark",
+	".vim":        "Vim Script",
+	".el":         "Emacs Lisp",
+}
+
+// languagesByInterpreter maps shebang interpreters to their language
+var languagesByInterpreter = map[string]string{
+	"sh":      "Shell",
+	"bash":    "Shell",
+	"zsh":     "Shell",
+	"ksh":     "Shell",
+	"dash":    "Shell",
+	"fish":    "Fish",
+	"python":  "Python",
+	"ruby":    "Ruby",
+	"perl":    "Perl",
+	"node":    "JavaScript",
+	"deno":    "TypeScript",
+	"php":     "PHP",
+	"lua":     "Lua",
+	"Rscript": "R",
+	"awk":     "Awk",
+	"gawk":    "Awk",
+	"tclsh":   "Tcl",
+	"pwsh":    "PowerShell",
+}
+
+// LanguageFromName detects a language from a file name alone.
+// Returns an empty string if the name is not recognised.
+func LanguageFromName(name string) string {
+	lower := strings.ToLower(name)
+	if lang, ok := languagesByFilename[lower]; ok {
+		return lang
+	}
+	
+	// Variants such as Dockerfile.dev or Makefile.inc
+	if prefix, _, found := strings.Cut(lower, "."); found {
+		if lang, ok := languagesByFilename[prefix]; ok {
+			return lang
+		}
+	}
+	
+	if lang, ok := languagesByExtension[filepath.Ext(lower)]; ok {
+		return lang
+	}
+	
+	return ""
+}
+
+// LanguageFromShebang detects a language from a "#!" interpreter line.
+// Returns an empty string if the line is not a recognised shebang.
+func LanguageFromShebang(line string) string {
+	if !strings.HasPrefix(line, "#!") {
+		return ""
+	}
+	
+	fields := strings.Fields(strings.TrimPrefix(line, "#!"))
+	if len(fields) == 0 {
+		return ""
+	}
+	
+	interpreter := filepath.Base(fields[0])
+	if interpreter == "env" {
+		// Skip env options such as -S to find the real interpreter
+		interpreter = ""
+		for _, field := range fields[1:] {
+			if !strings.HasPrefix(field, "-") && !strings.Contains(field, "=") {
+				interpreter = filepath.Base(field)
+				break
+			}
+		}
+	}
+	
+	// Strip version suffixes such as python3 or python3.11
+	interpreter = strings.TrimRight(interpreter, "0123456789.")
+	
+	return languagesByInterpreter[interpreter]
+}
+
+// blockComment is a pair of block comment delimiters
+type blockComment struct {
+	start string
+	end   string
+}
+
+// commentSyntax describes how comments and string literals are written in a language
+type commentSyntax struct {
+	lineComments  []string
+	blockComments []blockComment
+	nested        bool     // Block comments may be nested
+	quotes        []string // String delimiters, longest first
+	rawQuotes     []string // String delimiters without escape sequences
+}
+
+var (
+	cSyntax = commentSyntax{
+		lineComments:  []string{"//"},
+		blockComments: []blockComment{{"/*", "*/"}},
+		quotes:        []string{`"`, `'`},
+	}
+	nestedCSyntax = commentSyntax{
+		lineComments:  []string{"//"},
+		blockComments: []blockComment{{"/*", "*/"}},
+		nested:        true,
+		quotes:        []string{`"""`, `"`},
+	}
+	hashSyntax = commentSyntax{
+		lineComments: []string{"#"},
+		quotes:       []string{`"`, `'`},
+	}
+	markupSyntax = commentSyntax{
+		blockComments: []blockComment{{"<!--", "-->"}},
+	}
+	sqlSyntax = commentSyntax{
+		lineComments:  []string{"--"},
+		blockComments: []blockComment{{"/*", "*/"}},
+		quotes:        []string{`'`, `"`},
+	}
+)
+
+// commentSyntaxByLanguage maps language names to their comment syntax.
+// Languages without an entry have no comments: every non-blank line is code.
+var commentSyntaxByLanguage = map[string]commentSyntax{
+	"Go": {
+		lineComments:  []string{"//"},
+		blockComments: []blockComment{{"/*", "*/"}},
+		quotes:        []string{`"`, `'`},
+		rawQuotes:     []string{"`"},
+	},
+	"C":                cSyntax,
+	"C++":              cSyntax,
+	"C#":               cSyntax,
+	"Java":             cSyntax,
+	"Groovy":           cSyntax,
+	"Objective-C":      cSyntax,
+	"Objective-C++":    cSyntax,
+	"Protocol Buffers": cSyntax,
+	"CSS":              {blockComments: []blockComment{{"/*", "*/"}}, quotes: []string{`"`, `'`}},
+	"SCSS":             cSyntax,
+	"Less":             cSyntax,
+	"Sass":             cSyntax,
+	"PHP": {
+		lineComments:  []string{"//", "#"},
+		blockComments: []blockComment{{"/*", "*/"}},
+		quotes:        []string{`"`, `'`},
+	},
+	"JavaScript": {
+		lineComments:  []string{"//"},
+		blockComments: []blockComment{{"/*", "*/"}},
+		quotes:        []string{`"`, `'`},
+		rawQuotes:     []string{"`"},
+	},
+	"TypeScript": {
+		lineComments:  []string{"//"},
+		blockComments: []blockComment{{"/*", "*/"}},
+		quotes:        []string{`"`, `'`},
+		rawQuotes:     []string{"`"},
+	},
+	"Rust": {
+		lineComments:  []string{"//"},
+		blockComments: []blockComment{{"/*", "*/"}},
+		nested:        true,
+		quotes:        []string{`"`},
+	},
+	"Swift":  nestedCSyntax,
+	"Kotlin": nestedCSyntax,
+	"Scala":  nestedCSyntax,
+	"Dart":   nestedCSyntax,
+	"Zig":    {lineComments: []string{"//"}, quotes: []string{`"`, `'`}},
+	"Python": {
+		lineComments: []string{"#"},
+		quotes:       []string{`"""`, `'''`, `"`, `'`},
+	},
+	"Ruby": {
+		lineComments:  []string{"#"},
+		blockComments: []blockComment{{"=begin", "=end"}},
+		quotes:        []string{`"`, `'`},
+	},
+	"Shell":      hashSyntax,
+	"Fish":       hashSyntax,
+	"Perl":       hashSyntax,
+	"R":          hashSyntax,
+	"Elixir":     {lineComments: []string{"#"}, quotes: []string{`"""`, `"`, `'`}},
+	"Makefile":   hashSyntax,
+	"Dockerfile": hashSyntax,
+	"CMake":      hashSyntax,
+	"Starlark":   {lineComments: []string{"#"}, quotes: []string{`"""`, `'''`, `"`, `'`}},
+	"YAML":       hashSyntax,
+	"TOML":       hashSyntax,
+	"Terraform": {
+		lineComments:  []string{"#", "//"},
+		blockComments: []blockComment{{"/*", "*/"}},
+		quotes:        []string{`"`},
+	},
+	"HCL": {
+		lineComments:  []string{"#", "//"},
+		blockComments: []blockComment{{"/*", "*/"}},
+		quotes:        []string{`"`},
+	},
+	"GraphQL":    {lineComments: []string{"#"}, quotes: []string{`"""`, `"`}},
+	"PowerShell": {lineComments: []string{"#"}, blockComments: []blockComment{{"<#", "#>"}}, quotes: []string{`"`, `'`}},
+	"Awk":        hashSyntax,
+	"Tcl":        hashSyntax,
+	"Just":       hashSyntax,
+	"INI":        {lineComments: []string{";", "#"}},
+	"Julia": {
+		lineComments:  []string{"#"},
+		blockComments: []blockComment{{"#=", "=#"}},
+		nested:        true,
+		quotes:        []string{`"""`, `"`},
+	},
+	"Nim":     {lineComments: []string{"#"}, blockComments: []blockComment{{"#[", "]#"}}, nested: true, quotes: []string{`"""`, `"`}},
+	"SQL":     sqlSyntax,
+	"Lua":     {lineComments: []string{"--"}, blockComments: []blockComment{{"--[[", "]]"}}, quotes: []string{`"`, `'`}},
+	"Haskell": {lineComments: []string{"--"}, blockComments: []blockComment{{"{-", "-}"}}, nested: true, quotes: []string{`"`}},
+	"OCaml":   {blockComments: []blockComment{{"(*", "*)"}}, nested: true, quotes: []string{`"`}},
+	"F#": {
+		lineComments:  []string{"//"},
+		blockComments: []blockComment{{"(*", "*)"}},
+		nested:        true,
+		quotes:        []string{`"""`, `"`},
+	},
+	"Erlang":     {lineComments: []string{"%"}, quotes: []string{`"`}},
+	"TeX":        {lineComments: []string{"%"}},
+	"Clojure":    {lineComments: []string{";"}, quotes: []string{`"`}},
+	"Emacs Lisp": {lineComments: []string{";"}, quotes: []string{`"`}},
+	"Vim Script": {lineComments: []string{`"`}},
+	"Batch":      {lineComments: []string{"REM ", "rem ", "::"}},
+	"HTML":       markupSyntax,
+	"XML":        markupSyntax,
+	"Markdown":   markupSyntax,
+	"Vue":        {lineComments: []string{"//"}, blockComments: []blockComment{{"<!--", "-->"}, {"/*", "*/"}}, quotes: []string{`"`, `'`}},
+	"Svelte":     {lineComments: []string{"//"}, blockComments: []blockComment{{"<!--", "-->"}, {"/*", "*/"}}, quotes: []string{`"`, `'`}},
+}
\ No newline at end of file
@@ -0,0 +1,63 @@
+package scanner
+
+import (
+	"testing"
+)
+
+func TestLanguageFromName_Extension(t *testing.T) {
+	cases := map[string]string{
+		"main.go":       "Go",
+		"service.proto": "Protocol Buffers",
+		"config.yml":    "YAML",
+		"values.YAML":   "YAML",
+		"install.sh":    "Shell",
+		"README.md":     "Markdown",
+		"component.tsx": "TypeScript",
+	}
+	
+	for name, expected := range cases {
+		if got := LanguageFromName(name); got != expected {
+			t.Errorf("LanguageFromName(%q): expected '%s', got '%s'", name, expected, got)
+		}
+	}
+}
+
+func TestLanguageFromName_WellKnownFilenames(t *testing.T) {
+	cases := map[string]string{
+		"Makefile":       "Makefile",
+		"Dockerfile":     "Dockerfile",
+		"Dockerfile.dev": "Dockerfile",
+		"CMakeLists.txt": "CMake",
+		"Gemfile":        "Ruby",
+	}
+	
+	for name, expected := range cases {
+		if got := LanguageFromName(name); got != expected {
+			t.Errorf("LanguageFromName(%q): expected '%s', got '%s'", name, expected, got)
+		}
+	}
+}
+
+func TestLanguageFromName_Unknown(t *testing.T) {
+	if got := LanguageFromName("LICENSE"); got != "" {
+		t.Errorf("Expected no language for LICENSE, got '%s'", got)
+	}
+}
+
+func TestLanguageFromShebang(t *testing.T) {
+	cases := map[string]string{
+		"#!/bin/sh\n":                     "Shell",
+		"#!/usr/bin/env bash\n":           "Shell",
+		"#!/usr/bin/env python3\n":        "Python",
+		"#!/usr/bin/python3.11\n":         "Python",
+		"#!/usr/bin/env -S node --flag\n": "JavaScript",
+		"package main\n":                  "",
+		"#!\n":                            "",
+	}
+	
+	for line, expected := range cases {
+		if got := LanguageFromShebang(line); got != expected {
+			t.Errorf("LanguageFromShebang(%q): expected '%s', got '%s'", line, expected, got)
+		}
+	}
+}
\ No newline at end of file
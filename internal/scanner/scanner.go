@@ -1,9 +1,10 @@
 package scanner
 
 import (
+	"io/fs"
 	"os"
-	"path/filepath"
-	"strings"
+	
+	"github.com/user/loctree/internal/filter"
 )
 
 // ScanResult holds the results of scanning a directory
@@ -15,6 +16,12 @@ type ScanResult struct {
 
 // ScanDirectory recursively scans a directory and counts lines of code
 func ScanDirectory(dirPath string) (*ScanResult, error) {
+	return ScanDirectoryWithFilter(dirPath, nil)
+}
+
+// ScanDirectoryWithFilter recursively scans a directory and counts lines of code
+// in the files allowed by f (nil for defaults)
+func ScanDirectoryWithFilter(dirPath string, f *filter.Filter) (*ScanResult, error) {
 	// Check if directory exists
 	info, err := os.Stat(dirPath)
 	if err != nil {
@@ -24,46 +31,25 @@ func ScanDirectory(dirPath string) (*ScanResult, error) {
 		return nil, os.ErrNotExist
 	}
 	
-	result := &ScanResult{}
+	// The root directory counts as scanned
+	result := &ScanResult{DirsScanned: 1}
 	
-	err = filepath.WalkDir(dirPath, func(path string, d os.DirEntry, err error) error {
-		if err != nil {
-			// Skip directories we can't read
-			return nil
-		}
-		
-		// Skip hidden directories
+	fsys := os.DirFS(dirPath)
+	err = WalkFS(fsys, ".", f.BeginFS(fsys), func(relPath string, d fs.DirEntry) error {
 		if d.IsDir() {
 			result.DirsScanned++
-			if strings.HasPrefix(d.Name(), ".") && d.Name() != "." {
-				return filepath.SkipDir
-			}
-			return nil
-		}
-		
-		// Skip symbolic links
-		info, err := d.Info()
-		if err != nil {
-			return nil
-		}
-		if info.Mode()&os.ModeSymlink != 0 {
-			return nil
-		}
-		
-		// Skip hidden files
-		if strings.HasPrefix(d.Name(), ".") {
 			return nil
 		}
 		
-		// Count lines in regular files
-		lines, err := CountLines(path)
+		// Count lines the same way the tree does
+		stats, err := CountFileFS(fsys, relPath)
 		if err != nil {
 			// Skip files we can't read
 			return nil
 		}
 		
 		result.FilesScanned++
-		result.TotalLOC += lines
+		result.TotalLOC += stats.Lines
 		
 		return nil
 	})
@@ -73,4 +59,36 @@ func ScanDirectory(dirPath string) (*ScanResult, error) {
 	}
 	
 	return result, nil
+}
+
+// WalkFS walks dir, a slash-separated directory within fsys, calling fn for
+// each directory and file below it that walk allows. Hidden and filtered-out
+// entries are skipped before anything is opened, as are symbolic links and
+// directories that can't be read.
+func WalkFS(fsys fs.FS, dir string, walk *filter.Walk, fn func(relPath string, d fs.DirEntry) error) error {
+	return fs.WalkDir(fsys, dir, func(relPath string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return nil // Skip errors
+		}
+		
+		// Skip root
+		if relPath == dir {
+			return nil
+		}
+		
+		// Apply hidden-entry, ignore file and command-line filters during the walk
+		if !walk.Allow(relPath, d.IsDir()) {
+			if d.IsDir() {
+				return fs.SkipDir
+			}
+			return nil
+		}
+		
+		// Skip symbolic links
+		if d.Type()&fs.ModeSymlink != 0 {
+			return nil
+		}
+		
+		return fn(relPath, d)
+	})
 }
\ No newline at end of file
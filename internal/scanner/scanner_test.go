@@ -1,6 +1,7 @@
 package scanner
 
 import (
+	"os"
 	"path/filepath"
 	"testing"
 )
@@ -58,6 +59,36 @@ func TestScanDirectory_HiddenFiles(t *testing.T) {
 	}
 }
 
+func TestScanDirectory_RespectsIgnoreFiles(t *testing.T) {
+	root := t.TempDir()
+	files := map[string]string{
+		".gitignore":         "build/\n",
+		"main.go":            "package main\n",
+		"build/output.txt":   "a\nb\nc\n",
+		"sub/.loctreeignore": "*.txt\n",
+		"sub/notes.txt":      "a\nb\n",
+		"sub/code.go":        "package sub\n",
+	}
+	for name, content := range files {
+		path := filepath.Join(root, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	
+	result, err := ScanDirectory(root)
+	if err != nil {
+		t.Fatalf("Error scanning directory: %v", err)
+	}
+	// main.go (1) + sub/code.go (1)
+	if result.TotalLOC != 2 {
+		t.Errorf("Expected 2 total lines, got %d", result.TotalLOC)
+	}
+}
+
 func TestScanDirectory_NonExistent(t *testing.T) {
 	nonExistent := filepath.Join("testdata", "does_not_exist")
 	_, err := ScanDirectory(nonExistent)
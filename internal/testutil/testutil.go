@@ -0,0 +1,28 @@
+// Package testutil holds helpers shared by tests in several packages
+package testutil
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// WriteFile writes content to path, creating its directory
+func WriteFile(tb testing.TB, path, content string) {
+	tb.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		tb.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		tb.Fatal(err)
+	}
+}
+
+// WriteFiles creates files (and their directories) below root, by
+// slash-separated path
+func WriteFiles(tb testing.TB, root string, files map[string]string) {
+	tb.Helper()
+	for name, content := range files {
+		WriteFile(tb, filepath.Join(root, filepath.FromSlash(name)), content)
+	}
+}
\ No newline at end of file
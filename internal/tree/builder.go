@@ -1,51 +1,114 @@
 package tree
 
 import (
+	"io/fs"
 	"os"
+	"path"
 	"path/filepath"
-	"strings"
+	"runtime"
+	"sync"
 	
+	"github.com/user/loctree/internal/filter"
 	"github.com/user/loctree/internal/scanner"
 )
 
+// Options controls how a tree is built
+type Options struct {
+	Jobs   int            // Number of files counted in parallel (defaults to GOMAXPROCS)
+	Filter *filter.Filter // Decides which files and directories are scanned (nil for defaults)
+	FS     fs.FS          // Source of the files; nil scans the root path on disk
+}
+
+// fileJob is a file waiting to be counted by a worker
+type fileJob struct {
+	index   int
+	relPath string // Slash-separated path within the file source
+	size    int64
+	parent  *DirectoryNode
+}
+
+// fileResult is the outcome of counting a single file
+type fileResult struct {
+	job   fileJob
+	stats scanner.FileStats
+	err   error
+}
+
 // BuildTree builds a directory tree with LOC information
 func BuildTree(rootPath string) (*DirectoryNode, error) {
-	// Verify path exists
-	info, err := os.Stat(rootPath)
-	if err != nil {
-		return nil, err
+	return BuildTreeWithOptions(rootPath, Options{})
+}
+
+// BuildTreeWithOptions builds a directory tree with LOC information.
+// The directory walk feeds a bounded pool of workers that count files;
+// results are applied in walk order so the tree is identical to a sequential build.
+// When opts.FS is set the files are read from it instead of the disk, and
+// rootPath only names the nodes.
+func BuildTreeWithOptions(rootPath string, opts Options) (*DirectoryNode, error) {
+	fsys := opts.FS
+	if fsys == nil {
+		// Verify path exists
+		info, err := os.Stat(rootPath)
+		if err != nil {
+			return nil, err
+		}
+		if !info.IsDir() {
+			return nil, os.ErrNotExist
+		}
+		fsys = os.DirFS(rootPath)
 	}
-	if !info.IsDir() {
-		return nil, os.ErrNotExist
+	
+	return build(rootPath, fsys, ".", opts.Filter.BeginFS(fsys), opts.Jobs)
+}
+
+// build builds the tree of dir, a slash-separated directory within fsys, as
+// the subtree it would be of the tree of rootPath. The walk must already
+// have visited the directories above dir.
+func build(rootPath string, fsys fs.FS, dir string, walk *filter.Walk, workers int) (*DirectoryNode, error) {
+	if workers <= 0 {
+		workers = runtime.GOMAXPROCS(0)
 	}
 	
 	// Create root node
-	rootName := filepath.Base(rootPath)
-	root := NewDirectoryNode(rootName, rootPath)
+	root := NewDirectoryNode(filepath.Base(rootPath), rootPath)
+	if dir != "." {
+		root = NewDirectoryNode(path.Base(dir), filepath.Join(rootPath, filepath.FromSlash(dir)))
+	}
 	
-	// Map to store nodes by path for quick lookup
+	// Map to store nodes by relative path for quick lookup (only used by the walker)
 	nodeMap := make(map[string]*DirectoryNode)
-	nodeMap[rootPath] = root
+	nodeMap[dir] = root
 	
-	// Walk directory tree
-	err = filepath.WalkDir(rootPath, func(path string, d os.DirEntry, err error) error {
-		if err != nil {
-			return nil // Skip errors
-		}
-		
-		// Skip root (already created)
-		if path == rootPath {
-			return nil
-		}
-		
-		// Skip hidden directories
-		if d.IsDir() && strings.HasPrefix(d.Name(), ".") {
-			return filepath.SkipDir
+	// Start the counting workers
+	jobs := make(chan fileJob, workers*4)
+	results := make(chan fileResult, workers*4)
+	var wg sync.WaitGroup
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for job := range jobs {
+				stats, err := scanner.CountFileFS(fsys, job.relPath)
+				results <- fileResult{job: job, stats: stats, err: err}
+			}
+		}()
+	}
+	
+	// Collect results while the walk is still running so workers never block
+	collected := make(chan []fileResult)
+	go func() {
+		var all []fileResult
+		for result := range results {
+			all = append(all, result)
 		}
-		
+		collected <- all
+	}()
+	
+	// Walk directory tree
+	fileCount := 0
+	err := scanner.WalkFS(fsys, dir, walk, func(relPath string, d fs.DirEntry) error {
 		// Get parent path
-		parentPath := filepath.Dir(path)
-		parentNode, exists := nodeMap[parentPath]
+		parentNode, exists := nodeMap[path.Dir(relPath)]
 		if !exists {
 			// Parent doesn't exist (shouldn't happen in normal walk)
 			return nil
@@ -53,39 +116,45 @@ func BuildTree(rootPath string) (*DirectoryNode, error) {
 		
 		if d.IsDir() {
 			// Create directory node
-			node := NewDirectoryNode(d.Name(), path)
+			node := NewDirectoryNode(d.Name(), filepath.Join(rootPath, filepath.FromSlash(relPath)))
 			parentNode.AddChild(node)
-			nodeMap[path] = node
+			nodeMap[relPath] = node
 		} else {
-			// Skip symbolic links
 			info, err := d.Info()
 			if err != nil {
 				return nil
 			}
-			if info.Mode()&os.ModeSymlink != 0 {
-				return nil
-			}
-			
-			// Skip hidden files
-			if strings.HasPrefix(d.Name(), ".") {
-				return nil
-			}
 			
-			// Count lines in file and add to parent's FileLOC
-			lines, err := scanner.CountLines(path)
-			if err != nil {
-				return nil // Skip files we can't read
-			}
-			parentNode.FileLOC += lines
+			// Hand the file to a worker for counting
+			jobs <- fileJob{index: fileCount, relPath: relPath, size: info.Size(), parent: parentNode}
+			fileCount++
 		}
 		
 		return nil
 	})
 	
+	close(jobs)
+	wg.Wait()
+	close(results)
+	all := <-collected
+	
 	if err != nil {
 		return nil, err
 	}
 	
+	// Apply results in walk order, adding each file as a leaf of its directory
+	ordered := make([]fileResult, fileCount)
+	for _, result := range all {
+		ordered[result.job.index] = result
+	}
+	for _, result := range ordered {
+		if result.err != nil {
+			continue // Skip files we can't read
+		}
+		file := result.job.parent.AddCountedFile(path.Base(result.job.relPath), result.stats)
+		file.Size = result.job.size
+	}
+	
 	// Calculate total LOC for all nodes
 	root.CalculateLOC()
 	
@@ -93,4 +162,15 @@ func BuildTree(rootPath string) (*DirectoryNode, error) {
 	root.SortChildrenRecursive()
 	
 	return root, nil
+}
+
+// addFileStats adds a counted file to its directory's own totals
+func addFileStats(node *DirectoryNode, stats scanner.FileStats) {
+	node.FileLOC += stats.Lines
+	node.FileCode += stats.Code
+	node.FileComments += stats.Comments
+	node.FileBlanks += stats.Blanks
+	if stats.Lines > 0 {
+		node.FileLanguages[stats.Language] += stats.Lines
+	}
 }
\ No newline at end of file
@@ -1,8 +1,18 @@
 package tree
 
 import (
+	"fmt"
+	"io/fs"
+	"os"
 	"path/filepath"
+	"runtime"
+	"strings"
 	"testing"
+	"testing/fstest"
+	
+	"github.com/user/loctree/internal/filter"
+	"github.com/user/loctree/internal/scanner"
+	"github.com/user/loctree/internal/testutil"
 )
 
 func TestBuildTree_SingleDirectory(t *testing.T) {
@@ -69,6 +79,57 @@ func TestBuildTree_CalculatesLOC(t *testing.T) {
 	}
 }
 
+func TestBuildTree_DetectsLanguages(t *testing.T) {
+	testPath := filepath.Join("..", "scanner", "testdata", "test_project")
+	
+	tree, err := BuildTree(testPath)
+	if err != nil {
+		t.Fatalf("Error building tree: %v", err)
+	}
+	
+	// main.go and src/utils.go are both Go files
+	if tree.Languages["Go"] != 10 {
+		t.Errorf("Expected 10 Go LOC, got %d", tree.Languages["Go"])
+	}
+	if len(tree.Languages) != 1 {
+		t.Errorf("Expected only Go to be detected, got %v", tree.Languages)
+	}
+}
+
+func TestBuildTree_AddsFileLeaves(t *testing.T) {
+	testPath := filepath.Join("..", "scanner", "testdata", "test_project")
+	
+	tree, err := BuildTree(testPath)
+	if err != nil {
+		t.Fatalf("Error building tree: %v", err)
+	}
+	
+	// main.go and binary.bin live in the root; the hidden directory is skipped
+	if len(tree.Files) != 2 {
+		t.Fatalf("Expected 2 files in root, got %d", len(tree.Files))
+	}
+	if tree.FileCount != 3 {
+		t.Errorf("Expected 3 files in total, got %d", tree.FileCount)
+	}
+	
+	mainFile := tree.Files[0]
+	if mainFile.Name != "main.go" || !mainFile.IsFile {
+		t.Fatalf("Expected largest file to be main.go, got '%s'", mainFile.Name)
+	}
+	if mainFile.LOC != 5 {
+		t.Errorf("Expected main.go LOC 5, got %d", mainFile.LOC)
+	}
+	if mainFile.Language != "Go" {
+		t.Errorf("Expected main.go language 'Go', got '%s'", mainFile.Language)
+	}
+	if mainFile.Size == 0 {
+		t.Error("Expected main.go to have a size")
+	}
+	if mainFile.Parent != tree {
+		t.Error("Expected main.go parent to be the root")
+	}
+}
+
 func TestBuildTree_IgnoresHiddenDirectories(t *testing.T) {
 	testPath := filepath.Join("..", "scanner", "testdata", "test_project")
 	
@@ -90,4 +151,248 @@ func TestBuildTree_NonExistentPath(t *testing.T) {
 	if err == nil {
 		t.Error("Expected error for non-existent path")
 	}
+}
+
+// findChild returns the child node with the given name, or nil
+func findChild(node *DirectoryNode, name string) *DirectoryNode {
+	for _, child := range node.Children {
+		if child.Name == name {
+			return child
+		}
+	}
+	return nil
+}
+
+func TestBuildTree_RespectsIgnoreFiles(t *testing.T) {
+	root := t.TempDir()
+	testutil.WriteFiles(t, root, map[string]string{
+		".gitignore":            "node_modules/\n*.log\n",
+		".loctreeignore":        "/dist\n",
+		"main.go":               "package main\n",
+		"debug.log":             "one\ntwo\n",
+		"node_modules/lib/x.js": "a\nb\nc\n",
+		"dist/bundle.js":        "a\nb\n",
+		"web/dist/keep.js":      "a\n",
+		"web/.ignore":           "*.gen.js\n!keep.gen.js\n",
+		"web/api.gen.js":        "a\nb\nc\nd\n",
+		"web/keep.gen.js":       "a\nb\n",
+	})
+	
+	tree, err := BuildTree(root)
+	if err != nil {
+		t.Fatalf("Error building tree: %v", err)
+	}
+	
+	if findChild(tree, "node_modules") != nil {
+		t.Error("Expected node_modules to be ignored")
+	}
+	if findChild(tree, "dist") != nil {
+		t.Error("Expected anchored /dist to be ignored")
+	}
+	
+	// main.go (1) + web/dist/keep.js (1) + web/keep.gen.js (2)
+	if tree.LOC != 4 {
+		t.Errorf("Expected total LOC of 4, got %d", tree.LOC)
+	}
+}
+
+func TestBuildTreeWithOptions_NoIgnore(t *testing.T) {
+	root := t.TempDir()
+	testutil.WriteFiles(t, root, map[string]string{
+		".gitignore":    "vendor/\n",
+		"main.go":       "package main\n",
+		"vendor/lib.go": "package lib\n\nvar x = 1\n",
+	})
+	
+	tree, err := BuildTreeWithOptions(root, Options{Filter: &filter.Filter{NoIgnore: true}})
+	if err != nil {
+		t.Fatalf("Error building tree: %v", err)
+	}
+	
+	if findChild(tree, "vendor") == nil {
+		t.Error("Expected vendor to be included with NoIgnore")
+	}
+	if tree.LOC != 4 {
+		t.Errorf("Expected total LOC of 4, got %d", tree.LOC)
+	}
+}
+
+func TestBuildTreeWithOptions_SharesFilterWithScanner(t *testing.T) {
+	root := t.TempDir()
+	testutil.WriteFiles(t, root, map[string]string{
+		"main.go":             "package main\n",
+		"main_test.go":        "package main\n\n",
+		"api/service.proto":   "syntax = \"proto3\";\n",
+		"api/README.md":       "# API\n",
+		"testdata/fixture.go": "package testdata\n",
+		// A line longer than bufio.Scanner's 64KB limit
+		"long.go": "package main\n\nvar s = \"" + strings.Repeat("x", 70000) + "\"\n",
+	})
+	f := &filter.Filter{
+		Extensions:  []string{"go", "proto"},
+		Exclude:     []string{"*_test.go"},
+		ExcludeDirs: []string{"testdata"},
+	}
+	
+	tree, err := BuildTreeWithOptions(root, Options{Filter: f})
+	if err != nil {
+		t.Fatalf("Error building tree: %v", err)
+	}
+	result, err := scanner.ScanDirectoryWithFilter(root, f)
+	if err != nil {
+		t.Fatalf("Error scanning directory: %v", err)
+	}
+	
+	// main.go (1) + api/service.proto (1) + long.go (3)
+	if tree.LOC != 5 {
+		t.Errorf("Expected total LOC of 5, got %d", tree.LOC)
+	}
+	if result.TotalLOC != tree.LOC {
+		t.Errorf("Expected scanner and tree to agree, got %d and %d", result.TotalLOC, tree.LOC)
+	}
+	if result.FilesScanned != 3 {
+		t.Errorf("Expected 3 files scanned, got %d", result.FilesScanned)
+	}
+	if findChild(tree, "testdata") != nil {
+		t.Error("Expected testdata to be excluded")
+	}
+}
+
+// createSyntheticTree writes a directory tree of Go files for tests and benchmarks
+func createSyntheticTree(tb testing.TB, dirs, filesPerDir, linesPerFile int) string {
+	tb.Helper()
+	root := tb.TempDir()
+	
+	var content strings.Builder
+	content.WriteString("package synthetic\n\n// Synthetic file\n")
+	for i := 0; i < linesPerFile; i++ {
+		fmt.Fprintf(&content, "var value%d = \"/* %d */\"\n", i, i)
+	}
+	
+	for d := 0; d < dirs; d++ {
+		dir := filepath.Join(root, fmt.Sprintf("pkg%d", d%10), fmt.Sprintf("sub%d", d))
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			tb.Fatal(err)
+		}
+		for f := 0; f < filesPerDir; f++ {
+			file := filepath.Join(dir, fmt.Sprintf("file%d.go", f))
+			if err := os.WriteFile(file, []byte(content.String()), 0644); err != nil {
+				tb.Fatal(err)
+			}
+		}
+	}
+	
+	return root
+}
+
+// assertSameTree fails if two trees differ in structure or counts
+func assertSameTree(t *testing.T, expected, actual *DirectoryNode) {
+	t.Helper()
+	if expected.Path != actual.Path || expected.LOC != actual.LOC || expected.FileLOC != actual.FileLOC ||
+		expected.Code != actual.Code || expected.Comments != actual.Comments || expected.Blanks != actual.Blanks {
+		t.Fatalf("Node mismatch at %s: expected %+v, got %+v", expected.Path, expected, actual)
+	}
+	if fmt.Sprint(expected.Languages) != fmt.Sprint(actual.Languages) {
+		t.Fatalf("Language mismatch at %s: expected %v, got %v", expected.Path, expected.Languages, actual.Languages)
+	}
+	if len(expected.Files) != len(actual.Files) {
+		t.Fatalf("File count mismatch at %s: expected %d, got %d", expected.Path, len(expected.Files), len(actual.Files))
+	}
+	for i := range expected.Files {
+		if expected.Files[i].Path != actual.Files[i].Path || expected.Files[i].LOC != actual.Files[i].LOC {
+			t.Fatalf("File mismatch at %s: expected %s (%d), got %s (%d)", expected.Path,
+				expected.Files[i].Path, expected.Files[i].LOC, actual.Files[i].Path, actual.Files[i].LOC)
+		}
+	}
+	if len(expected.Children) != len(actual.Children) {
+		t.Fatalf("Child count mismatch at %s: expected %d, got %d", expected.Path, len(expected.Children), len(actual.Children))
+	}
+	for i := range expected.Children {
+		assertSameTree(t, expected.Children[i], actual.Children[i])
+	}
+}
+
+func TestBuildTreeWithOptions_ParallelMatchesSequential(t *testing.T) {
+	root := createSyntheticTree(t, 30, 5, 20)
+	
+	sequential, err := BuildTreeWithOptions(root, Options{Jobs: 1})
+	if err != nil {
+		t.Fatalf("Error building sequential tree: %v", err)
+	}
+	parallel, err := BuildTreeWithOptions(root, Options{Jobs: 8})
+	if err != nil {
+		t.Fatalf("Error building parallel tree: %v", err)
+	}
+	
+	assertSameTree(t, sequential, parallel)
+}
+
+func BenchmarkBuildTree_Sequential(b *testing.B) {
+	root := createSyntheticTree(b, 100, 10, 200)
+	b.ResetTimer()
+	
+	for i := 0; i < b.N; i++ {
+		if _, err := BuildTreeWithOptions(root, Options{Jobs: 1}); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkBuildTree_Parallel(b *testing.B) {
+	root := createSyntheticTree(b, 100, 10, 200)
+	b.ResetTimer()
+	
+	for i := 0; i < b.N; i++ {
+		if _, err := BuildTreeWithOptions(root, Options{Jobs: runtime.GOMAXPROCS(0)}); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func TestBuildTreeWithOptions_FS(t *testing.T) {
+	fsys := fstest.MapFS{
+		"main.go":         {Data: []byte("package main\n\nfunc main() {}\n")},
+		"pkg/util.go":     {Data: []byte("package pkg\n")},
+		"build/out.go":    {Data: []byte("package out\n")},
+		".gitignore":      {Data: []byte("build/\n")},
+		"link.go":         {Data: []byte("main.go"), Mode: fs.ModeSymlink},
+		"docs/.hidden.md": {Data: []byte("# hidden\n")},
+	}
+	
+	root, err := BuildTreeWithOptions("/repo", Options{FS: fsys})
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	
+	if root.LOC != 4 {
+		t.Errorf("Expected 4 lines from main.go and pkg/util.go, got %d", root.LOC)
+	}
+	if findChild(root, "build") != nil {
+		t.Error("Expected the ignore file in the FS to be respected")
+	}
+	pkg := findChild(root, "pkg")
+	if pkg == nil || pkg.Path != filepath.Join("/repo", "pkg") {
+		t.Fatalf("Expected pkg at /repo/pkg, got %+v", pkg)
+	}
+	if len(pkg.Files) != 1 || pkg.Files[0].Path != filepath.Join("/repo", "pkg", "util.go") {
+		t.Errorf("Expected util.go under /repo/pkg, got %+v", pkg.Files)
+	}
+	if len(root.Files) != 1 {
+		t.Errorf("Expected symbolic links to be skipped, got %d files", len(root.Files))
+	}
+}
+
+func TestBuildTreeWithOptions_FSMatchesDisk(t *testing.T) {
+	root := createSyntheticTree(t, 10, 3, 10)
+	
+	disk, err := BuildTree(root)
+	if err != nil {
+		t.Fatalf("Error building tree from disk: %v", err)
+	}
+	fromFS, err := BuildTreeWithOptions(root, Options{FS: os.DirFS(root)})
+	if err != nil {
+		t.Fatalf("Error building tree from FS: %v", err)
+	}
+	
+	assertSameTree(t, disk, fromFS)
 }
\ No newline at end of file
@@ -0,0 +1,218 @@
+package tree
+
+import "fmt"
+
+// ChangeStatus describes how a node differs between two scans
+type ChangeStatus int
+
+const (
+	Unchanged ChangeStatus = iota // Present in both scans with the same lines
+	Added                         // Only present in the newer scan
+	Removed                       // Only present in the earlier scan
+	Changed                       // Present in both scans with lines added or removed
+)
+
+var changeStatusNames = []string{"unchanged", "added", "removed", "changed"}
+
+// String returns the name of the status
+func (s ChangeStatus) String() string {
+	if s < 0 || int(s) >= len(changeStatusNames) {
+		return fmt.Sprintf("ChangeStatus(%d)", int(s))
+	}
+	return changeStatusNames[s]
+}
+
+// Change records how a node differs from an earlier scan. Added and Removed
+// sum the growth and shrinkage of individual files where both scans list
+// them, and of each directory's own FileLOC otherwise, so a directory that
+// moved code between files shows both.
+type Change struct {
+	Status  ChangeStatus
+	OldLOC  int // Total LOC in the earlier scan
+	Added   int // Lines gained (including children)
+	Removed int // Lines lost (including children)
+}
+
+// Delta returns the net change in lines
+func (c *Change) Delta() int {
+	return c.Added - c.Removed
+}
+
+// Churn returns the absolute change: lines added plus lines removed
+func (c *Change) Churn() int {
+	return c.Added + c.Removed
+}
+
+// Diff compares two scans of the same tree, matching nodes by their path
+// relative to the root. It returns a new tree with the counts of after and a
+// Change on every node; nodes that only exist in before are kept with zero
+// counts so that removals stay visible.
+func Diff(before, after *DirectoryNode) *DirectoryNode {
+	return diffNode(before, after)
+}
+
+// diffNode merges a pair of matching nodes, either of which may be nil
+func diffNode(before, after *DirectoryNode) *DirectoryNode {
+	node := copyNode(before, after)
+	change := &Change{Status: Unchanged}
+	node.Change = change
+	
+	switch {
+	case before == nil:
+		change.Status = Added
+	case after == nil:
+		change.Status = Removed
+	}
+	if before != nil {
+		change.OldLOC = before.LOC
+	}
+	
+	if node.IsFile {
+		addLineDelta(change, lineCount(before, true), lineCount(after, true))
+		return node.finishChange()
+	}
+	
+	for _, pair := range pairByName(children(before), children(after)) {
+		child := diffNode(pair[0], pair[1])
+		node.AddChild(child)
+		change.Added += child.Change.Added
+		change.Removed += child.Change.Removed
+	}
+	
+	// Files are compared one by one when both scans list them
+	perFile := listsFiles(before) && listsFiles(after)
+	for _, pair := range pairByName(files(before), files(after)) {
+		file := diffNode(pair[0], pair[1])
+		node.AddFile(file)
+		if perFile {
+			change.Added += file.Change.Added
+			change.Removed += file.Change.Removed
+		}
+	}
+	if !perFile {
+		addLineDelta(change, lineCount(before, false), lineCount(after, false))
+	}
+	
+	return node.finishChange()
+}
+
+// finishChange marks a node present in both scans as changed when any lines
+// were added or removed
+func (n *DirectoryNode) finishChange() *DirectoryNode {
+	if n.Change.Status == Unchanged && n.Change.Churn() > 0 {
+		n.Change.Status = Changed
+	}
+	return n
+}
+
+// copyNode copies the identity and counts of after, or only the identity of
+// before when the node was removed
+func copyNode(before, after *DirectoryNode) *DirectoryNode {
+	source := after
+	if source == nil {
+		source = before
+	}
+	
+	node := NewDirectoryNode(source.Name, source.Path)
+	node.IsFile = source.IsFile
+	node.Language = source.Language
+	if after == nil {
+		return node
+	}
+	
+	node.Size = after.Size
+	node.LOC = after.LOC
+	node.FileLOC = after.FileLOC
+	node.Code = after.Code
+	node.Comments = after.Comments
+	node.Blanks = after.Blanks
+	node.FileCode = after.FileCode
+	node.FileComments = after.FileComments
+	node.FileBlanks = after.FileBlanks
+	node.FileCount = after.FileCount
+	for lang, loc := range after.Languages {
+		node.Languages[lang] = loc
+	}
+	for lang, loc := range after.FileLanguages {
+		node.FileLanguages[lang] = loc
+	}
+	return node
+}
+
+// addLineDelta adds the difference between two line counts to change
+func addLineDelta(change *Change, before, after int) {
+	if after > before {
+		change.Added += after - before
+	} else {
+		change.Removed += before - after
+	}
+}
+
+// lineCount returns a file's lines, or the lines of the files directly in a
+// directory; a missing node has none
+func lineCount(n *DirectoryNode, isFile bool) int {
+	switch {
+	case n == nil:
+		return 0
+	case isFile:
+		return n.LOC
+	default:
+		return n.FileLOC
+	}
+}
+
+// listsFiles reports whether a scan has file leaves for a directory. Scans
+// without files still account for the directory's lines through FileLOC.
+func listsFiles(n *DirectoryNode) bool {
+	return n == nil || len(n.Files) > 0 || n.FileLOC == 0
+}
+
+func children(n *DirectoryNode) []*DirectoryNode {
+	if n == nil {
+		return nil
+	}
+	return n.Children
+}
+
+func files(n *DirectoryNode) []*DirectoryNode {
+	if n == nil {
+		return nil
+	}
+	return n.Files
+}
+
+// pairByName matches nodes with the same name, in the order of after
+// followed by the nodes that only exist in before
+func pairByName(before, after []*DirectoryNode) [][2]*DirectoryNode {
+	byName := make(map[string]*DirectoryNode, len(before))
+	for _, n := range before {
+		byName[n.Name] = n
+	}
+	
+	pairs := make([][2]*DirectoryNode, 0, len(after))
+	for _, n := range after {
+		pairs = append(pairs, [2]*DirectoryNode{byName[n.Name], n})
+		delete(byName, n.Name)
+	}
+	for _, n := range before {
+		if _, removed := byName[n.Name]; removed {
+			pairs = append(pairs, [2]*DirectoryNode{n, nil})
+		}
+	}
+	return pairs
+}
+
+// SortChildrenRecursiveByChange sorts all children and files by their
+// absolute change (lines added plus removed), largest first, then by name.
+// Nodes without a Change sort as unchanged.
+func (n *DirectoryNode) SortChildrenRecursiveByChange() {
+	n.SortChildrenRecursiveByOrder(Order{Key: SortChange}, MetricLines)
+}
+
+// churn returns the absolute change of a node, zero without a Change
+func churn(n *DirectoryNode) int {
+	if n.Change == nil {
+		return 0
+	}
+	return n.Change.Churn()
+}
\ No newline at end of file
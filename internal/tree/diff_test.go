@@ -0,0 +1,99 @@
+package tree
+
+import "testing"
+
+// diffFixture builds root (main.go) -> pkg (util.go) with the given line counts
+func diffFixture(mainLOC, utilLOC int) *DirectoryNode {
+	root := NewDirectoryNode("root", "/root")
+	pkg := NewDirectoryNode("pkg", "/root/pkg")
+	root.AddChild(pkg)
+	
+	mainFile := NewFileNode("main.go", "/root/main.go")
+	mainFile.FileLOC = mainLOC
+	root.AddFile(mainFile)
+	root.FileLOC = mainLOC
+	
+	util := NewFileNode("util.go", "/root/pkg/util.go")
+	util.FileLOC = utilLOC
+	pkg.AddFile(util)
+	pkg.FileLOC = utilLOC
+	
+	root.CalculateLOC()
+	return root
+}
+
+func TestDiff_Changed(t *testing.T) {
+	root := Diff(diffFixture(10, 5), diffFixture(12, 2))
+	
+	if root.LOC != 14 {
+		t.Errorf("Expected the new total of 14, got %d", root.LOC)
+	}
+	change := root.Change
+	if change.Status != Changed || change.OldLOC != 15 || change.Added != 2 || change.Removed != 3 {
+		t.Errorf("Unexpected root change: %+v", change)
+	}
+	if change.Delta() != -1 || change.Churn() != 5 {
+		t.Errorf("Expected delta -1 and churn 5, got %d and %d", change.Delta(), change.Churn())
+	}
+	
+	pkg := root.Children[0]
+	if pkg.Change.Status != Changed || pkg.Change.Removed != 3 || pkg.Files[0].Change.Removed != 3 {
+		t.Errorf("Unexpected pkg change: %+v", pkg.Change)
+	}
+}
+
+func TestDiff_AddedAndRemoved(t *testing.T) {
+	before := diffFixture(10, 5)
+	after := diffFixture(10, 5)
+	
+	// Move util.go from pkg into a new directory
+	lib := NewDirectoryNode("lib", "/root/lib")
+	moved := after.Children[0].Files[0]
+	after.Children = nil
+	after.AddChild(lib)
+	lib.AddFile(moved)
+	lib.FileLOC = 5
+	after.CalculateLOC()
+	
+	root := Diff(before, after)
+	if root.Change.Status != Changed || root.Change.Added != 5 || root.Change.Removed != 5 {
+		t.Errorf("Expected a move to show as +5 / -5, got %+v", root.Change)
+	}
+	
+	if len(root.Children) != 2 {
+		t.Fatalf("Expected the added and removed directories, got %d children", len(root.Children))
+	}
+	added, removed := root.Children[0], root.Children[1]
+	if added.Name != "lib" || added.Change.Status != Added {
+		t.Errorf("Expected 'lib' to be added, got '%s' %s", added.Name, added.Change.Status)
+	}
+	if removed.Name != "pkg" || removed.Change.Status != Removed || removed.LOC != 0 || removed.Change.OldLOC != 5 {
+		t.Errorf("Expected 'pkg' to be removed with no lines left, got %+v", removed.Change)
+	}
+}
+
+func TestDiff_WithoutFiles(t *testing.T) {
+	before := diffFixture(10, 5)
+	before.Files = nil
+	
+	root := Diff(before, diffFixture(12, 5))
+	if root.Change.Added != 2 || root.Change.Removed != 0 {
+		t.Errorf("Expected FileLOC to be compared when files are missing, got %+v", root.Change)
+	}
+}
+
+func TestSortChildrenRecursiveByChange(t *testing.T) {
+	after := diffFixture(10, 5)
+	other := NewDirectoryNode("other", "/root/other")
+	other.FileLOC = 1
+	after.AddChild(other)
+	after.CalculateLOC()
+	
+	root := Diff(diffFixture(10, 1), after)
+	root.SortChildrenRecursiveByChange()
+	
+	// pkg gained 4 lines, other gained 1
+	if root.Children[0].Name != "pkg" || root.Children[1].Name != "other" {
+		t.Errorf("Expected children sorted by absolute change, got %s, %s", root.Children[0].Name, root.Children[1].Name)
+	}
+}
\ No newline at end of file
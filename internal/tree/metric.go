@@ -0,0 +1,52 @@
+package tree
+
+import "fmt"
+
+// Metric selects which line count is displayed and used for sorting
+type Metric int
+
+const (
+	MetricLines    Metric = iota // All lines
+	MetricCode                   // Code lines only
+	MetricComments               // Comment lines only
+	MetricBlanks                 // Blank lines only
+)
+
+var metricNames = []string{"lines", "code", "comments", "blanks"}
+
+// String returns the name of the metric
+func (m Metric) String() string {
+	if m < 0 || int(m) >= len(metricNames) {
+		return fmt.Sprintf("Metric(%d)", int(m))
+	}
+	return metricNames[m]
+}
+
+// Next returns the metric that follows m, wrapping around
+func (m Metric) Next() Metric {
+	return Metric((int(m) + 1) % len(metricNames))
+}
+
+// ParseMetric converts a metric name into a Metric
+func ParseMetric(name string) (Metric, error) {
+	for i, metricName := range metricNames {
+		if name == metricName {
+			return Metric(i), nil
+		}
+	}
+	return MetricLines, fmt.Errorf("unknown metric %q (expected lines, code, comments or blanks)", name)
+}
+
+// Count returns the total for the given metric, including children
+func (n *DirectoryNode) Count(metric Metric) int {
+	switch metric {
+	case MetricCode:
+		return n.Code
+	case MetricComments:
+		return n.Comments
+	case MetricBlanks:
+		return n.Blanks
+	default:
+		return n.LOC
+	}
+}
\ No newline at end of file
@@ -0,0 +1,79 @@
+package tree
+
+import (
+	"testing"
+)
+
+func TestParseMetric(t *testing.T) {
+	for _, metric := range []Metric{MetricLines, MetricCode, MetricComments, MetricBlanks} {
+		parsed, err := ParseMetric(metric.String())
+		if err != nil {
+			t.Errorf("Unexpected error parsing '%s': %v", metric, err)
+		}
+		if parsed != metric {
+			t.Errorf("Expected %v, got %v", metric, parsed)
+		}
+	}
+}
+
+func TestParseMetric_Unknown(t *testing.T) {
+	_, err := ParseMetric("words")
+	if err == nil {
+		t.Error("Expected error for unknown metric")
+	}
+}
+
+func TestMetricNext_Wraps(t *testing.T) {
+	if MetricLines.Next() != MetricCode {
+		t.Errorf("Expected code after lines, got %v", MetricLines.Next())
+	}
+	if MetricBlanks.Next() != MetricLines {
+		t.Errorf("Expected lines after blanks, got %v", MetricBlanks.Next())
+	}
+}
+
+func TestCalculateLOC_RollsUpLineKinds(t *testing.T) {
+	root := NewDirectoryNode("root", "/root")
+	root.FileCode = 10
+	root.FileComments = 2
+	root.FileBlanks = 1
+	
+	child := NewDirectoryNode("child", "/root/child")
+	child.FileCode = 5
+	child.FileComments = 3
+	child.FileBlanks = 4
+	
+	root.AddChild(child)
+	root.CalculateLOC()
+	
+	if root.Count(MetricCode) != 15 {
+		t.Errorf("Expected 15 code lines, got %d", root.Count(MetricCode))
+	}
+	if root.Count(MetricComments) != 5 {
+		t.Errorf("Expected 5 comment lines, got %d", root.Count(MetricComments))
+	}
+	if root.Count(MetricBlanks) != 5 {
+		t.Errorf("Expected 5 blank lines, got %d", root.Count(MetricBlanks))
+	}
+}
+
+func TestSortChildrenBy_Metric(t *testing.T) {
+	root := NewDirectoryNode("root", "/root")
+	
+	commented := NewDirectoryNode("commented", "/root/commented")
+	commented.LOC = 100
+	commented.Code = 10
+	
+	dense := NewDirectoryNode("dense", "/root/dense")
+	dense.LOC = 50
+	dense.Code = 45
+	
+	root.AddChild(commented)
+	root.AddChild(dense)
+	
+	root.SortChildrenBy(MetricCode)
+	
+	if root.Children[0].Name != "dense" {
+		t.Errorf("Expected 'dense' first when sorting by code, got '%s'", root.Children[0].Name)
+	}
+}
\ No newline at end of file
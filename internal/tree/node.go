@@ -1,60 +1,250 @@
 package tree
 
-import "sort"
+import (
+	"path/filepath"
+	"sort"
+	"strings"
+	
+	"github.com/user/loctree/internal/scanner"
+)
 
-// DirectoryNode represents a directory in the tree structure
+// DirectoryNode represents a directory in the tree structure, or a file
+// leaf underneath one when IsFile is set
 type DirectoryNode struct {
-	Name       string
-	Path       string
-	LOC        int              // Total LOC (including children)
-	FileLOC    int              // LOC from files in this directory only
-	Children   []*DirectoryNode
-	IsExpanded bool
-	Parent     *DirectoryNode
+	Name          string
+	Path          string
+	IsFile        bool           // Node is a file leaf rather than a directory
+	Language      string         // Detected language (files only)
+	Size          int64          // Size in bytes (files only)
+	LOC           int            // Total LOC (including children)
+	FileLOC       int            // LOC from files in this directory only
+	Code          int            // Total code lines (including children)
+	Comments      int            // Total comment lines (including children)
+	Blanks        int            // Total blank lines (including children)
+	FileCode      int            // Code lines from files in this directory only
+	FileComments  int            // Comment lines from files in this directory only
+	FileBlanks    int            // Blank lines from files in this directory only
+	Languages     map[string]int // Total LOC per language (including children)
+	FileLanguages map[string]int // LOC per language from files in this directory only
+	FileCount     int            // Total number of files (including children)
+	Children      []*DirectoryNode
+	Files         []*DirectoryNode // File leaves directly in this directory
+	IsExpanded    bool
+	Parent        *DirectoryNode
+	Change        *Change // Difference from an earlier scan, set by Diff
+}
+
+// LanguageLOC pairs a language name with its line count
+type LanguageLOC struct {
+	Language string
+	LOC      int
 }
 
 // NewDirectoryNode creates a new directory node
 func NewDirectoryNode(name, path string) *DirectoryNode {
 	return &DirectoryNode{
-		Name:       name,
-		Path:       path,
-		LOC:        0,
-		FileLOC:    0,
-		Children:   []*DirectoryNode{},
-		IsExpanded: false,
-		Parent:     nil,
+		Name:          name,
+		Path:          path,
+		LOC:           0,
+		FileLOC:       0,
+		Languages:     map[string]int{},
+		FileLanguages: map[string]int{},
+		Children:      []*DirectoryNode{},
+		Files:         []*DirectoryNode{},
+		IsExpanded:    false,
+		Parent:        nil,
 	}
 }
 
+// NewFileNode creates a new file leaf node
+func NewFileNode(name, path string) *DirectoryNode {
+	node := NewDirectoryNode(name, path)
+	node.IsFile = true
+	return node
+}
+
 // AddChild adds a child node and establishes parent-child relationship
 func (n *DirectoryNode) AddChild(child *DirectoryNode) {
 	n.Children = append(n.Children, child)
 	child.Parent = n
 }
 
+// AddFile adds a file leaf and establishes the parent relationship
+func (n *DirectoryNode) AddFile(file *DirectoryNode) {
+	n.Files = append(n.Files, file)
+	file.Parent = n
+}
+
+// AddCountedFile adds a file leaf called name with the given counts, and
+// adds them to the directory's own totals. CalculateLOC must be called
+// afterwards to update the totals of the tree.
+func (n *DirectoryNode) AddCountedFile(name string, stats scanner.FileStats) *DirectoryNode {
+	file := NewFileNode(name, filepath.Join(n.Path, name))
+	file.Language = stats.Language
+	addFileStats(file, stats)
+	n.AddFile(file)
+	addFileStats(n, stats)
+	return file
+}
+
+// ChildNames returns the names of the child directories in order,
+// separated by spaces
+func (n *DirectoryNode) ChildNames() string {
+	names := make([]string, len(n.Children))
+	for i, child := range n.Children {
+		names[i] = child.Name
+	}
+	return strings.Join(names, " ")
+}
+
+// RelativePath returns the node's path relative to the tree root, using
+// forward slashes ("." for the root itself)
+func (n *DirectoryNode) RelativePath() string {
+	if n.Parent == nil {
+		return "."
+	}
+	
+	var parts []string
+	for current := n; current.Parent != nil; current = current.Parent {
+		parts = append(parts, current.Name)
+	}
+	for i, j := 0, len(parts)-1; i < j; i, j = i+1, j-1 {
+		parts[i], parts[j] = parts[j], parts[i]
+	}
+	
+	return strings.Join(parts, "/")
+}
+
+// Depth returns the number of ancestors between the node and the tree root
+func (n *DirectoryNode) Depth() int {
+	depth := 0
+	for current := n; current.Parent != nil; current = current.Parent {
+		depth++
+	}
+	return depth
+}
+
+// HasVisibleChildren reports whether expanding the node would show anything
+func (n *DirectoryNode) HasVisibleChildren(showFiles bool) bool {
+	return len(n.Children) > 0 || (showFiles && len(n.Files) > 0)
+}
+
 // CalculateLOC recursively calculates the total LOC for this node and all children
 func (n *DirectoryNode) CalculateLOC() {
+	for _, file := range n.Files {
+		file.CalculateLOC()
+	}
+	for _, child := range n.Children {
+		child.CalculateLOC()
+	}
+	n.rollUp()
+}
+
+// rollUp sets the node's totals from its own files and the totals of its
+// children, which must already be calculated
+func (n *DirectoryNode) rollUp() {
 	// Start with files in this directory
 	n.LOC = n.FileLOC
+	n.Code = n.FileCode
+	n.Comments = n.FileComments
+	n.Blanks = n.FileBlanks
+	n.Languages = make(map[string]int, len(n.FileLanguages))
+	for lang, loc := range n.FileLanguages {
+		n.Languages[lang] = loc
+	}
+	
+	// File leaves are already included in FileLOC
+	n.FileCount = len(n.Files)
+	if n.IsFile {
+		n.FileCount = 1
+	}
 	
-	// Recursively calculate for children and add to total
+	// Add the children's totals
 	for _, child := range n.Children {
-		child.CalculateLOC()
 		n.LOC += child.LOC
+		n.Code += child.Code
+		n.Comments += child.Comments
+		n.Blanks += child.Blanks
+		n.FileCount += child.FileCount
+		for lang, loc := range child.Languages {
+			n.Languages[lang] += loc
+		}
 	}
 }
 
+// LanguageBreakdown returns the per-language LOC totals, largest first
+func (n *DirectoryNode) LanguageBreakdown() []LanguageLOC {
+	breakdown := make([]LanguageLOC, 0, len(n.Languages))
+	for lang, loc := range n.Languages {
+		breakdown = append(breakdown, LanguageLOC{Language: lang, LOC: loc})
+	}
+	
+	sort.Slice(breakdown, func(i, j int) bool {
+		if breakdown[i].LOC != breakdown[j].LOC {
+			return breakdown[i].LOC > breakdown[j].LOC
+		}
+		return breakdown[i].Language < breakdown[j].Language
+	})
+	
+	return breakdown
+}
+
+// DirCount returns the number of directories below the node, not counting
+// the node itself
+func (n *DirectoryNode) DirCount() int {
+	count := len(n.Children)
+	for _, child := range n.Children {
+		count += child.DirCount()
+	}
+	return count
+}
+
+// LargestFiles returns up to limit file leaves below the node with the
+// highest count for metric, largest first and then by path. A file returns
+// itself.
+func (n *DirectoryNode) LargestFiles(metric Metric, limit int) []*DirectoryNode {
+	if n.IsFile {
+		return []*DirectoryNode{n}
+	}
+	
+	var files []*DirectoryNode
+	var collect func(dir *DirectoryNode)
+	collect = func(dir *DirectoryNode) {
+		files = append(files, dir.Files...)
+		for _, child := range dir.Children {
+			collect(child)
+		}
+	}
+	collect(n)
+	
+	sort.SliceStable(files, func(i, j int) bool {
+		if ci, cj := files[i].Count(metric), files[j].Count(metric); ci != cj {
+			return ci > cj
+		}
+		return files[i].Path < files[j].Path
+	})
+	if len(files) > limit {
+		files = files[:limit]
+	}
+	return files
+}
+
 // SortChildren sorts the immediate children by LOC (descending)
 func (n *DirectoryNode) SortChildren() {
-	sort.Slice(n.Children, func(i, j int) bool {
-		return n.Children[i].LOC > n.Children[j].LOC
-	})
+	n.SortChildrenBy(MetricLines)
 }
 
 // SortChildrenRecursive sorts all children and their descendants by LOC (descending)
 func (n *DirectoryNode) SortChildrenRecursive() {
-	n.SortChildren()
-	for _, child := range n.Children {
-		child.SortChildrenRecursive()
-	}
+	n.SortChildrenRecursiveBy(MetricLines)
+}
+
+// SortChildrenBy sorts the immediate children and files by the given metric (descending)
+func (n *DirectoryNode) SortChildrenBy(metric Metric) {
+	n.sortChildren(Order{}, metric, "")
+}
+
+// SortChildrenRecursiveBy sorts all children and their descendants by the given metric (descending)
+func (n *DirectoryNode) SortChildrenRecursiveBy(metric Metric) {
+	n.sortRecursive(Order{}, metric, "")
 }
\ No newline at end of file
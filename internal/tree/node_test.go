@@ -1,7 +1,10 @@
 package tree
 
 import (
+	"strings"
 	"testing"
+	
+	"github.com/user/loctree/internal/scanner"
 )
 
 func TestNewDirectoryNode(t *testing.T) {
@@ -143,4 +146,214 @@ func TestSortChildren_Recursive(t *testing.T) {
 	if child.Children[1].Name != "gc1" {
 		t.Errorf("Expected second grandchild to be 'gc1' (LOC=5), got '%s'", child.Children[1].Name)
 	}
+}
+
+func TestCalculateLOC_Languages(t *testing.T) {
+	root := NewDirectoryNode("root", "/root")
+	root.FileLanguages["Go"] = 10
+	
+	child := NewDirectoryNode("child", "/root/child")
+	child.FileLanguages["Go"] = 20
+	child.FileLanguages["YAML"] = 5
+	
+	root.AddChild(child)
+	root.CalculateLOC()
+	
+	if root.Languages["Go"] != 30 {
+		t.Errorf("Expected root Go LOC 30, got %d", root.Languages["Go"])
+	}
+	if root.Languages["YAML"] != 5 {
+		t.Errorf("Expected root YAML LOC 5, got %d", root.Languages["YAML"])
+	}
+	if child.Languages["Go"] != 20 {
+		t.Errorf("Expected child Go LOC 20, got %d", child.Languages["Go"])
+	}
+}
+
+func TestLanguageBreakdown_SortedByLOC(t *testing.T) {
+	node := NewDirectoryNode("root", "/root")
+	node.FileLanguages["Shell"] = 95
+	node.FileLanguages["Go"] = 12340
+	node.FileLanguages["YAML"] = 800
+	node.CalculateLOC()
+	
+	breakdown := node.LanguageBreakdown()
+	
+	expected := []string{"Go", "YAML", "Shell"}
+	if len(breakdown) != len(expected) {
+		t.Fatalf("Expected %d languages, got %d", len(expected), len(breakdown))
+	}
+	for i, lang := range expected {
+		if breakdown[i].Language != lang {
+			t.Errorf("Expected language %d to be '%s', got '%s'", i, lang, breakdown[i].Language)
+		}
+	}
+}
+
+func TestNewFileNode(t *testing.T) {
+	file := NewFileNode("main.go", "/root/main.go")
+	
+	if !file.IsFile {
+		t.Error("Expected file node to have IsFile set")
+	}
+	if file.Name != "main.go" {
+		t.Errorf("Expected name 'main.go', got '%s'", file.Name)
+	}
+}
+
+func TestAddFile(t *testing.T) {
+	dir := NewDirectoryNode("dir", "/dir")
+	file := NewFileNode("main.go", "/dir/main.go")
+	
+	dir.AddFile(file)
+	
+	if len(dir.Files) != 1 || dir.Files[0] != file {
+		t.Error("File not added correctly")
+	}
+	if len(dir.Children) != 0 {
+		t.Error("Expected files to be kept separate from child directories")
+	}
+	if file.Parent != dir {
+		t.Error("Parent relationship not established")
+	}
+}
+
+func TestCalculateLOC_WithFiles(t *testing.T) {
+	root := NewDirectoryNode("root", "/root")
+	child := NewDirectoryNode("child", "/root/child")
+	root.AddChild(child)
+	
+	rootFile := NewFileNode("main.go", "/root/main.go")
+	rootFile.FileLOC = 10
+	root.AddFile(rootFile)
+	root.FileLOC = 10
+	
+	for _, name := range []string{"a.go", "b.go"} {
+		file := NewFileNode(name, "/root/child/"+name)
+		file.FileLOC = 5
+		child.AddFile(file)
+		child.FileLOC += 5
+	}
+	
+	root.CalculateLOC()
+	
+	if root.LOC != 20 {
+		t.Errorf("Expected root LOC 20 (files must not be counted twice), got %d", root.LOC)
+	}
+	if rootFile.LOC != 10 {
+		t.Errorf("Expected file LOC 10, got %d", rootFile.LOC)
+	}
+	if root.FileCount != 3 {
+		t.Errorf("Expected root FileCount 3, got %d", root.FileCount)
+	}
+	if child.FileCount != 2 {
+		t.Errorf("Expected child FileCount 2, got %d", child.FileCount)
+	}
+}
+
+func TestHasVisibleChildren(t *testing.T) {
+	dir := NewDirectoryNode("dir", "/dir")
+	dir.AddFile(NewFileNode("main.go", "/dir/main.go"))
+	
+	if dir.HasVisibleChildren(false) {
+		t.Error("Expected directory with only files to have no visible children when files are hidden")
+	}
+	if !dir.HasVisibleChildren(true) {
+		t.Error("Expected directory with files to have visible children when files are shown")
+	}
+}
+
+func TestSortChildren_SortsFiles(t *testing.T) {
+	dir := NewDirectoryNode("dir", "/dir")
+	small := NewFileNode("small.go", "/dir/small.go")
+	small.LOC = 5
+	large := NewFileNode("large.go", "/dir/large.go")
+	large.LOC = 50
+	dir.AddFile(small)
+	dir.AddFile(large)
+	
+	dir.SortChildren()
+	
+	if dir.Files[0] != large {
+		t.Errorf("Expected 'large.go' first, got '%s'", dir.Files[0].Name)
+	}
+}
+
+func TestRelativePathAndDepth(t *testing.T) {
+	root := NewDirectoryNode("root", "/root")
+	child := NewDirectoryNode("child", "/root/child")
+	file := NewFileNode("main.go", "/root/child/main.go")
+	root.AddChild(child)
+	child.AddFile(file)
+	
+	if root.RelativePath() != "." || root.Depth() != 0 {
+		t.Errorf("Expected root at '.' depth 0, got '%s' depth %d", root.RelativePath(), root.Depth())
+	}
+	if file.RelativePath() != "child/main.go" || file.Depth() != 2 {
+		t.Errorf("Expected 'child/main.go' depth 2, got '%s' depth %d", file.RelativePath(), file.Depth())
+	}
+}
+
+func TestDirCountAndLargestFiles(t *testing.T) {
+	root := NewDirectoryNode("root", "/root")
+	pkg := NewDirectoryNode("pkg", "/root/pkg")
+	sub := NewDirectoryNode("sub", "/root/pkg/sub")
+	docs := NewDirectoryNode("docs", "/root/docs")
+	root.AddChild(pkg)
+	root.AddChild(docs)
+	pkg.AddChild(sub)
+	
+	for _, f := range []struct {
+		dir  *DirectoryNode
+		name string
+		loc  int
+	}{
+		{root, "main.go", 50},
+		{pkg, "a.go", 200},
+		{sub, "b.go", 50},
+		{sub, "c.go", 120},
+	} {
+		file := NewFileNode(f.name, f.dir.Path+"/"+f.name)
+		file.FileLOC = f.loc
+		f.dir.AddFile(file)
+	}
+	root.CalculateLOC()
+	
+	if count := root.DirCount(); count != 3 {
+		t.Errorf("Expected 3 directories below root, got %d", count)
+	}
+	if count := sub.DirCount(); count != 0 {
+		t.Errorf("Expected no directories below sub, got %d", count)
+	}
+	
+	largest := root.LargestFiles(MetricLines, 3)
+	var names []string
+	for _, file := range largest {
+		names = append(names, file.Name)
+	}
+	if strings.Join(names, " ") != "a.go c.go main.go" {
+		t.Errorf("Expected a.go c.go main.go, got %v", names)
+	}
+	
+	file := sub.Files[0]
+	if largest := file.LargestFiles(MetricLines, 5); len(largest) != 1 || largest[0] != file {
+		t.Errorf("Expected a file to return itself, got %v", largest)
+	}
+}
+
+func TestAddCountedFile(t *testing.T) {
+	root := NewDirectoryNode("root", "/root")
+	file := root.AddCountedFile("main.go", codeStats("Go", 10))
+	root.AddCountedFile("README.md", scanner.FileStats{Language: "Markdown", LineCounts: scanner.LineCounts{Lines: 4, Blanks: 1, Comments: 3}})
+	root.CalculateLOC()
+	
+	if file.Path != "/root/main.go" || file.Parent != root || file.Language != "Go" || file.LOC != 10 {
+		t.Errorf("Unexpected file: %s with %d %s lines", file.Path, file.LOC, file.Language)
+	}
+	if root.FileLOC != 14 || root.FileCode != 10 || root.FileComments != 3 || root.FileBlanks != 1 {
+		t.Errorf("Expected the counts in the directory's own totals, got %d lines", root.FileLOC)
+	}
+	if root.FileLanguages["Markdown"] != 4 || root.FileCount != 2 {
+		t.Errorf("Expected 4 Markdown lines in 2 files, got %d in %d", root.FileLanguages["Markdown"], root.FileCount)
+	}
 }
\ No newline at end of file
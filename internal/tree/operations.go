@@ -1,24 +1,117 @@
 package tree
 
+import "strings"
+
 // ToggleExpanded toggles the expanded state of a directory node
 func (n *DirectoryNode) ToggleExpanded() {
 	n.IsExpanded = !n.IsExpanded
 }
 
-// GetVisibleNodes returns a flat list of currently visible nodes
+// AllDepths is a maximum depth that includes every level of the tree
+const AllDepths = -1
+
+// ExpandToDepth expands every directory above maxDepth and collapses the rest,
+// so that nodes down to maxDepth (the root is depth 0) become visible.
+// A negative maxDepth, such as AllDepths, expands the whole tree.
+func ExpandToDepth(root *DirectoryNode, maxDepth int) {
+	expandToDepth(root, 0, maxDepth)
+}
+
+// expandToDepth recursively sets the expanded state below node
+func expandToDepth(node *DirectoryNode, depth, maxDepth int) {
+	node.IsExpanded = maxDepth < 0 || depth < maxDepth
+	for _, child := range node.Children {
+		expandToDepth(child, depth+1, maxDepth)
+	}
+}
+
+// GetVisibleNodes returns a flat list of currently visible directory nodes
 func GetVisibleNodes(root *DirectoryNode) []*DirectoryNode {
+	return GetVisibleNodesWithFiles(root, false)
+}
+
+// GetVisibleNodesWithFiles returns a flat list of currently visible nodes.
+// When showFiles is set, each expanded directory lists its files after its subdirectories.
+func GetVisibleNodesWithFiles(root *DirectoryNode, showFiles bool) []*DirectoryNode {
 	var visible []*DirectoryNode
-	addVisibleNodes(root, &visible)
+	addVisibleNodes(root, showFiles, &visible)
 	return visible
 }
 
 // addVisibleNodes recursively adds visible nodes to the list
-func addVisibleNodes(node *DirectoryNode, visible *[]*DirectoryNode) {
+func addVisibleNodes(node *DirectoryNode, showFiles bool, visible *[]*DirectoryNode) {
 	*visible = append(*visible, node)
 	
 	if node.IsExpanded {
 		for _, child := range node.Children {
-			addVisibleNodes(child, visible)
+			addVisibleNodes(child, showFiles, visible)
+		}
+		if showFiles {
+			*visible = append(*visible, node.Files...)
+		}
+	}
+}
+
+// ExpandAncestors expands every directory above node so that it becomes visible
+func ExpandAncestors(node *DirectoryNode) {
+	for current := node.Parent; current != nil; current = current.Parent {
+		current.IsExpanded = true
+	}
+}
+
+// FindPath returns the node at a slash-separated path relative to root, or
+// the deepest node along that path when the rest of it is not in the tree
+func FindPath(root *DirectoryNode, relPath string) *DirectoryNode {
+	node := root
+	for _, name := range strings.Split(relPath, "/") {
+		if name == "." || name == "" {
+			continue
+		}
+		next := findByName(node.Children, name)
+		if next == nil {
+			next = findByName(node.Files, name)
+		}
+		if next == nil {
+			return node
+		}
+		node = next
+	}
+	return node
+}
+
+// findByName returns the node with the given name, or nil
+func findByName(nodes []*DirectoryNode, name string) *DirectoryNode {
+	for _, node := range nodes {
+		if node.Name == name {
+			return node
+		}
+	}
+	return nil
+}
+
+// ExpandAll expands node and every directory below it
+func ExpandAll(node *DirectoryNode) {
+	node.IsExpanded = true
+	for _, child := range node.Children {
+		ExpandAll(child)
+	}
+}
+
+// CollapseAll collapses node and every directory below it
+func CollapseAll(node *DirectoryNode) {
+	node.IsExpanded = false
+	for _, child := range node.Children {
+		CollapseAll(child)
+	}
+}
+
+// CopyExpansion expands and collapses the directories of to like the
+// directories with the same relative path in from
+func CopyExpansion(from, to *DirectoryNode) {
+	to.IsExpanded = from.IsExpanded
+	for _, child := range to.Children {
+		if match := findByName(from.Children, child.Name); match != nil {
+			CopyExpansion(match, child)
 		}
 	}
 }
\ No newline at end of file
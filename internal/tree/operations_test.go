@@ -64,4 +64,120 @@ func TestGetVisibleNodes_SomeExpanded(t *testing.T) {
 	if len(visible) != 4 {
 		t.Errorf("Expected 4 visible nodes, got %d", len(visible))
 	}
+}
+
+func TestGetVisibleNodesWithFiles(t *testing.T) {
+	root := NewDirectoryNode("root", "/root")
+	child := NewDirectoryNode("child", "/root/child")
+	file := NewFileNode("main.go", "/root/main.go")
+	childFile := NewFileNode("util.go", "/root/child/util.go")
+	
+	root.AddChild(child)
+	root.AddFile(file)
+	child.AddFile(childFile)
+	root.IsExpanded = true
+	
+	hidden := GetVisibleNodesWithFiles(root, false)
+	if len(hidden) != 2 {
+		t.Errorf("Expected 2 visible nodes with files hidden, got %d", len(hidden))
+	}
+	
+	shown := GetVisibleNodesWithFiles(root, true)
+	// root, child (collapsed), main.go
+	if len(shown) != 3 {
+		t.Fatalf("Expected 3 visible nodes with files shown, got %d", len(shown))
+	}
+	if shown[1] != child || shown[2] != file {
+		t.Error("Expected files to be listed after subdirectories")
+	}
+}
+
+func TestExpandToDepth(t *testing.T) {
+	root := NewDirectoryNode("root", "/root")
+	child := NewDirectoryNode("child", "/root/child")
+	grandchild := NewDirectoryNode("grandchild", "/root/child/grandchild")
+	root.AddChild(child)
+	child.AddChild(grandchild)
+	grandchild.IsExpanded = true
+	
+	ExpandToDepth(root, 1)
+	if !root.IsExpanded || child.IsExpanded || grandchild.IsExpanded {
+		t.Error("Expected only the root to be expanded at depth 1")
+	}
+	
+	ExpandToDepth(root, AllDepths)
+	if !root.IsExpanded || !child.IsExpanded || !grandchild.IsExpanded {
+		t.Error("Expected every directory to be expanded at AllDepths")
+	}
+	
+	ExpandToDepth(root, 0)
+	if root.IsExpanded || child.IsExpanded || grandchild.IsExpanded {
+		t.Error("Expected every directory to be collapsed at depth 0")
+	}
+}
+
+func TestExpandAncestors(t *testing.T) {
+	root := NewDirectoryNode("root", "/root")
+	child := NewDirectoryNode("child", "/root/child")
+	grandchild := NewDirectoryNode("grandchild", "/root/child/grandchild")
+	root.AddChild(child)
+	child.AddChild(grandchild)
+	
+	ExpandAncestors(grandchild)
+	
+	if !root.IsExpanded || !child.IsExpanded {
+		t.Error("Expected the ancestors to be expanded")
+	}
+	if grandchild.IsExpanded {
+		t.Error("Expected the node itself to stay collapsed")
+	}
+	
+	visible := GetVisibleNodes(root)
+	if len(visible) != 3 || visible[2] != grandchild {
+		t.Errorf("Expected grandchild to be visible, got %d nodes", len(visible))
+	}
+}
+
+func TestFindPath(t *testing.T) {
+	root := NewDirectoryNode("root", "/root")
+	child := NewDirectoryNode("child", "/root/child")
+	file := NewFileNode("main.go", "/root/child/main.go")
+	root.AddChild(child)
+	child.AddFile(file)
+	
+	tests := []struct {
+		path     string
+		expected *DirectoryNode
+	}{
+		{".", root},
+		{"child", child},
+		{"child/main.go", file},
+		{"child/missing/deeper", child},
+		{"missing", root},
+	}
+	
+	for _, tt := range tests {
+		if got := FindPath(root, tt.path); got != tt.expected {
+			t.Errorf("FindPath(%q): expected %s, got %s", tt.path, tt.expected.Name, got.Name)
+		}
+	}
+}
+
+func TestExpandAllAndCollapseAll(t *testing.T) {
+	root := NewDirectoryNode("root", "/root")
+	child := NewDirectoryNode("child", "/root/child")
+	grandchild := NewDirectoryNode("grandchild", "/root/child/grandchild")
+	root.AddChild(child)
+	child.AddChild(grandchild)
+	
+	ExpandAll(child)
+	if root.IsExpanded || !child.IsExpanded || !grandchild.IsExpanded {
+		t.Error("Expected only child and its subtree to be expanded")
+	}
+	
+	root.IsExpanded = true
+	CollapseAll(root)
+	if root.IsExpanded || child.IsExpanded || grandchild.IsExpanded {
+		t.Error("Expected every directory to be collapsed")
+	}
 }
\ No newline at end of file
@@ -0,0 +1,222 @@
+package tree
+
+import (
+	"fmt"
+	"path"
+	"strconv"
+	"strings"
+)
+
+// Query selects the nodes kept by Prune. Empty fields match everything.
+type Query struct {
+	Patterns  []string // Globs matched against names, or against relative paths when they contain a slash
+	Languages []string // Languages a file must be written in
+	MinLines  int      // Smallest count, by the pruning metric, of a node that stays listed
+}
+
+// ParseQuery parses a filter expression of space-separated terms: a name
+// glob such as "*.go" or "testdata", "lang:Go", or a minimum line count such
+// as ">1000" or ">=1000". A file must match one of the globs and one of the
+// languages given. Spaces in language names may be written as - or _.
+func ParseQuery(expr string) (Query, error) {
+	var q Query
+	for _, term := range strings.Fields(expr) {
+		switch {
+		case strings.HasPrefix(term, "lang:"):
+			for _, lang := range strings.Split(strings.TrimPrefix(term, "lang:"), ",") {
+				if lang != "" {
+					q.Languages = append(q.Languages, lang)
+				}
+			}
+			
+		case strings.HasPrefix(term, ">"):
+			min, err := parseMinLines(term)
+			if err != nil {
+				return Query{}, err
+			}
+			q.MinLines = min
+			
+		default:
+			if _, err := path.Match(term, ""); err != nil {
+				return Query{}, fmt.Errorf("invalid pattern %q", term)
+			}
+			q.Patterns = append(q.Patterns, term)
+		}
+	}
+	return q, nil
+}
+
+// parseMinLines parses ">N" or ">=N" into the smallest count that passes
+func parseMinLines(term string) (int, error) {
+	value, inclusive := strings.TrimPrefix(term, ">"), false
+	if strings.HasPrefix(value, "=") {
+		value, inclusive = value[1:], true
+	}
+	n, err := strconv.Atoi(strings.ReplaceAll(value, ",", ""))
+	if err != nil || n < 0 {
+		return 0, fmt.Errorf("invalid line count %q", term)
+	}
+	if !inclusive {
+		n++
+	}
+	return n, nil
+}
+
+// IsEmpty reports whether the query keeps the whole tree
+func (q Query) IsEmpty() bool {
+	return len(q.Patterns) == 0 && len(q.Languages) == 0 && q.MinLines == 0
+}
+
+// Prune returns a copy of the tree holding only the files that match the
+// globs and languages of q and the directories above them, with every count
+// recomputed from the files kept. A directory matching a glob keeps all files
+// below it that match the languages. Nodes counting fewer than q.MinLines by
+// metric are then left out of the listing, but their lines stay in their
+// parents' totals. Directories without file leaves (scans made without
+// files) only know their own lines per language, so a language filter keeps
+// those lines but not their code, comment and blank split.
+//
+// The copy is fully expanded, and shares each node's Change with the
+// original.
+func Prune(root *DirectoryNode, q Query, metric Metric) *DirectoryNode {
+	pruned := pruneNode(root, q, "", false)
+	if pruned == nil {
+		pruned = copyIdentity(root)
+	}
+	if q.MinLines > 0 {
+		dropSmall(pruned, q.MinLines, metric)
+	}
+	return pruned
+}
+
+// pruneNode copies the matching part of a directory, or returns nil when
+// nothing in it matches. rel is the directory's relative path, empty for
+// the root, and matched is set when an ancestor matched a glob.
+func pruneNode(n *DirectoryNode, q Query, rel string, matched bool) *DirectoryNode {
+	matched = matched || len(q.Patterns) == 0 || (rel != "" && q.matchesName(n.Name, rel))
+	node := copyIdentity(n)
+	
+	for _, file := range n.Files {
+		fileRel := joinRelative(rel, file.Name)
+		if (matched || q.matchesName(file.Name, fileRel)) && q.matchesLanguage(file.Language) {
+			copied := copyIdentity(file)
+			copyOwnCounts(copied, file, nil)
+			copied.rollUp()
+			node.AddFile(copied)
+			copyOwnCounts(node, file, nil)
+		}
+	}
+	if len(n.Files) == 0 && matched {
+		var keep func(string) bool
+		if len(q.Languages) > 0 {
+			keep = q.matchesLanguage
+		}
+		copyOwnCounts(node, n, keep)
+	}
+	
+	for _, child := range n.Children {
+		if copied := pruneNode(child, q, joinRelative(rel, child.Name), matched); copied != nil {
+			node.AddChild(copied)
+		}
+	}
+	
+	node.rollUp()
+	if node.FileLOC == 0 && len(node.Files) == 0 && len(node.Children) == 0 && !(matched && len(q.Languages) == 0) {
+		return nil
+	}
+	return node
+}
+
+// copyIdentity copies a node's name, path and file details into a new,
+// expanded node without any counts
+func copyIdentity(n *DirectoryNode) *DirectoryNode {
+	node := NewDirectoryNode(n.Name, n.Path)
+	node.IsFile = n.IsFile
+	node.Language = n.Language
+	node.Size = n.Size
+	node.Change = n.Change
+	node.IsExpanded = !n.IsFile
+	return node
+}
+
+// copyOwnCounts adds the lines of a file, or the lines of the files directly
+// in a directory, to node's own counts. When keep is set, only the lines of
+// the languages it accepts are added and the code, comment and blank split
+// is left out.
+func copyOwnCounts(node, source *DirectoryNode, keep func(string) bool) {
+	if keep == nil {
+		node.FileLOC += source.FileLOC
+		node.FileCode += source.FileCode
+		node.FileComments += source.FileComments
+		node.FileBlanks += source.FileBlanks
+	}
+	for lang, loc := range source.FileLanguages {
+		if keep == nil || keep(lang) {
+			node.FileLanguages[lang] += loc
+			if keep != nil {
+				node.FileLOC += loc
+			}
+		}
+	}
+}
+
+// dropSmall removes the children and files counting fewer than min lines
+// from the listing without changing any totals
+func dropSmall(n *DirectoryNode, min int, metric Metric) {
+	keep := func(nodes []*DirectoryNode) []*DirectoryNode {
+		kept := nodes[:0]
+		for _, node := range nodes {
+			if node.Count(metric) >= min {
+				kept = append(kept, node)
+			}
+		}
+		return kept
+	}
+	n.Children = keep(n.Children)
+	n.Files = keep(n.Files)
+	for _, child := range n.Children {
+		dropSmall(child, min, metric)
+	}
+}
+
+// matchesName reports whether a name, or a relative path for globs with a
+// slash, matches one of the query's globs
+func (q Query) matchesName(name, rel string) bool {
+	for _, pattern := range q.Patterns {
+		target := name
+		if strings.Contains(pattern, "/") {
+			target = rel
+		}
+		if ok, _ := path.Match(pattern, target); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// matchesLanguage reports whether a language is one the query accepts
+func (q Query) matchesLanguage(lang string) bool {
+	if len(q.Languages) == 0 {
+		return true
+	}
+	for _, want := range q.Languages {
+		if normalizeLanguage(want) == normalizeLanguage(lang) {
+			return true
+		}
+	}
+	return false
+}
+
+// normalizeLanguage lower-cases a language name and drops spaces, dashes and
+// underscores, so "vim-script" matches "Vim Script"
+func normalizeLanguage(lang string) string {
+	return strings.NewReplacer(" ", "", "-", "", "_", "").Replace(strings.ToLower(lang))
+}
+
+// joinRelative appends a name to a relative path, where the root is empty
+func joinRelative(rel, name string) string {
+	if rel == "" {
+		return name
+	}
+	return rel + "/" + name
+}
\ No newline at end of file
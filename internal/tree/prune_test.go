@@ -0,0 +1,159 @@
+package tree
+
+import (
+	"testing"
+	
+	"github.com/user/loctree/internal/scanner"
+)
+
+// newPruneTree builds
+//
+//	root/main.go (Go, 100)
+//	root/pkg/util.go (Go, 40), root/pkg/util_test.go (Go, 60)
+//	root/pkg/testdata/input.yaml (YAML, 500)
+//	root/docs (no files, 30 Markdown lines)
+func newPruneTree() *DirectoryNode {
+	root := NewDirectoryNode("root", "/root")
+	pkg := NewDirectoryNode("pkg", "/root/pkg")
+	testdata := NewDirectoryNode("testdata", "/root/pkg/testdata")
+	docs := NewDirectoryNode("docs", "/root/docs")
+	root.AddChild(pkg)
+	root.AddChild(docs)
+	pkg.AddChild(testdata)
+	
+	root.AddCountedFile("main.go", codeStats("Go", 100))
+	pkg.AddCountedFile("util.go", codeStats("Go", 40))
+	pkg.AddCountedFile("util_test.go", codeStats("Go", 60))
+	testdata.AddCountedFile("input.yaml", codeStats("YAML", 500))
+	docs.FileLOC = 30
+	docs.FileCode = 20
+	docs.FileBlanks = 10
+	docs.FileLanguages["Markdown"] = 30
+	
+	root.CalculateLOC()
+	return root
+}
+
+// codeStats returns the counts of a file of loc code lines
+func codeStats(language string, loc int) scanner.FileStats {
+	return scanner.FileStats{Language: language, LineCounts: scanner.LineCounts{Lines: loc, Code: loc}}
+}
+
+func TestParseQuery(t *testing.T) {
+	q, err := ParseQuery("*.go lang:go,vim-script >=1,000 internal/*")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(q.Patterns) != 2 || q.Patterns[0] != "*.go" || q.Patterns[1] != "internal/*" {
+		t.Errorf("Expected patterns [*.go internal/*], got %v", q.Patterns)
+	}
+	if len(q.Languages) != 2 || !q.matchesLanguage("Vim Script") || !q.matchesLanguage("Go") {
+		t.Errorf("Expected languages go and vim-script, got %v", q.Languages)
+	}
+	if q.MinLines != 1000 {
+		t.Errorf("Expected MinLines 1000, got %d", q.MinLines)
+	}
+	
+	if q, _ := ParseQuery(">99"); q.MinLines != 100 {
+		t.Errorf("Expected >99 to mean at least 100 lines, got %d", q.MinLines)
+	}
+	if q, _ := ParseQuery("  "); !q.IsEmpty() {
+		t.Errorf("Expected an empty query, got %+v", q)
+	}
+	
+	for _, expr := range []string{"[", ">x", ">-1"} {
+		if _, err := ParseQuery(expr); err == nil {
+			t.Errorf("Expected an error for %q", expr)
+		}
+	}
+}
+
+func TestPrune_Glob(t *testing.T) {
+	root := newPruneTree()
+	q, _ := ParseQuery("*_test.go")
+	
+	pruned := Prune(root, q, MetricLines)
+	
+	if pruned.LOC != 60 || pruned.FileCount != 1 {
+		t.Errorf("Expected 60 lines in 1 file, got %d in %d", pruned.LOC, pruned.FileCount)
+	}
+	if len(pruned.Children) != 1 || pruned.Children[0].Name != "pkg" {
+		t.Fatalf("Expected only pkg to be kept, got %d children", len(pruned.Children))
+	}
+	pkg := pruned.Children[0]
+	if len(pkg.Children) != 0 || len(pkg.Files) != 1 || pkg.Files[0].Name != "util_test.go" {
+		t.Errorf("Expected pkg to keep only util_test.go, got %d children and %d files", len(pkg.Children), len(pkg.Files))
+	}
+	if !pruned.IsExpanded || !pkg.IsExpanded {
+		t.Error("Expected the pruned tree to be expanded")
+	}
+	
+	// The original tree is left untouched
+	if root.LOC != 730 || len(root.Children) != 2 {
+		t.Errorf("Expected the original tree to keep 730 lines, got %d", root.LOC)
+	}
+}
+
+func TestPrune_DirectoryGlob(t *testing.T) {
+	root := newPruneTree()
+	q, _ := ParseQuery("testdata docs")
+	
+	pruned := Prune(root, q, MetricLines)
+	
+	if pruned.LOC != 530 {
+		t.Errorf("Expected 530 lines, got %d", pruned.LOC)
+	}
+	if len(pruned.Files) != 0 {
+		t.Errorf("Expected main.go to be left out, got %d files", len(pruned.Files))
+	}
+	if pruned.Children[0].Name != "pkg" || pruned.Children[0].FileLOC != 0 {
+		t.Errorf("Expected pkg to be kept only as an ancestor, got %+v", pruned.Children[0])
+	}
+}
+
+func TestPrune_Language(t *testing.T) {
+	root := newPruneTree()
+	
+	q, _ := ParseQuery("lang:go")
+	pruned := Prune(root, q, MetricLines)
+	if pruned.LOC != 200 || pruned.Languages["Go"] != 200 || len(pruned.Languages) != 1 {
+		t.Errorf("Expected 200 Go lines, got %d (%v)", pruned.LOC, pruned.Languages)
+	}
+	if len(pruned.Children) != 1 || len(pruned.Children[0].Children) != 0 {
+		t.Error("Expected testdata and docs to be left out")
+	}
+	
+	// Directories without file leaves keep their lines per language only
+	q, _ = ParseQuery("lang:markdown")
+	pruned = Prune(root, q, MetricLines)
+	if pruned.LOC != 30 || pruned.Code != 0 {
+		t.Errorf("Expected 30 Markdown lines without a code count, got %d and %d", pruned.LOC, pruned.Code)
+	}
+	
+	q, _ = ParseQuery("lang:rust")
+	pruned = Prune(root, q, MetricLines)
+	if pruned.LOC != 0 || len(pruned.Children) != 0 || len(pruned.Files) != 0 {
+		t.Errorf("Expected an empty root, got %d lines", pruned.LOC)
+	}
+}
+
+func TestPrune_MinLines(t *testing.T) {
+	root := newPruneTree()
+	q, _ := ParseQuery(">=100")
+	
+	pruned := Prune(root, q, MetricLines)
+	
+	if pruned.LOC != 730 {
+		t.Errorf("Expected the totals to be kept, got %d", pruned.LOC)
+	}
+	if len(pruned.Children) != 1 || pruned.Children[0].Name != "pkg" {
+		t.Fatalf("Expected only pkg to be listed, got %d children", len(pruned.Children))
+	}
+	pkg := pruned.Children[0]
+	if len(pkg.Files) != 0 || len(pkg.Children) != 1 {
+		t.Errorf("Expected pkg to list only testdata, got %d files and %d children", len(pkg.Files), len(pkg.Children))
+	}
+	if len(pruned.Files) != 1 || pruned.Files[0].Name != "main.go" {
+		t.Errorf("Expected main.go to be listed, got %d files", len(pruned.Files))
+	}
+}
\ No newline at end of file
@@ -0,0 +1,129 @@
+package tree
+
+import (
+	"sort"
+	"strings"
+)
+
+// Search finds the nodes below root whose relative path matches query, best
+// match first. Each slash-separated part of the query must match a path
+// component in order, as a substring or as a subsequence of its letters, and
+// the last part must match the node's own name, so "int/sc" finds
+// internal/scanner. Matching ignores case.
+func Search(root *DirectoryNode, query string, includeFiles bool) []*DirectoryNode {
+	terms := splitQuery(query)
+	if len(terms) == 0 {
+		return nil
+	}
+	
+	var results []searchResult
+	visit := func(node *DirectoryNode, parts []string) {
+		if score, ok := matchPath(terms, parts); ok {
+			results = append(results, searchResult{node: node, score: score, path: strings.Join(parts, "/")})
+		}
+	}
+	var walk func(node *DirectoryNode, parts []string)
+	walk = func(node *DirectoryNode, parts []string) {
+		for _, child := range node.Children {
+			childParts := append(parts[:len(parts):len(parts)], strings.ToLower(child.Name))
+			visit(child, childParts)
+			walk(child, childParts)
+		}
+		if includeFiles {
+			for _, file := range node.Files {
+				visit(file, append(parts[:len(parts):len(parts)], strings.ToLower(file.Name)))
+			}
+		}
+	}
+	walk(root, nil)
+	
+	// Better scores first, then shallower paths, then alphabetically
+	sort.SliceStable(results, func(i, j int) bool {
+		a, b := results[i], results[j]
+		if a.score != b.score {
+			return a.score > b.score
+		}
+		if depthA, depthB := strings.Count(a.path, "/"), strings.Count(b.path, "/"); depthA != depthB {
+			return depthA < depthB
+		}
+		return a.path < b.path
+	})
+	
+	nodes := make([]*DirectoryNode, len(results))
+	for i, result := range results {
+		nodes[i] = result.node
+	}
+	return nodes
+}
+
+// searchResult is a matching node with its score and lower-case path
+type searchResult struct {
+	node  *DirectoryNode
+	score int
+	path  string
+}
+
+// splitQuery splits a search query into its lower-case, non-empty parts
+func splitQuery(query string) []string {
+	var terms []string
+	for _, term := range strings.Split(strings.ToLower(strings.TrimSpace(query)), "/") {
+		if term != "" {
+			terms = append(terms, term)
+		}
+	}
+	return terms
+}
+
+// matchPath reports whether the query terms match the path components and
+// how well. The last term must match the last component; the others are
+// matched in order against the components above it.
+func matchPath(terms, parts []string) (int, bool) {
+	if len(parts) < len(terms) {
+		return 0, false
+	}
+	
+	last := len(terms) - 1
+	score, ok := matchPart(terms[last], parts[len(parts)-1])
+	if !ok {
+		return 0, false
+	}
+	
+	next := 0
+	for _, term := range terms[:last] {
+		matched := false
+		for ; next < len(parts)-1 && !matched; next++ {
+			var partScore int
+			if partScore, matched = matchPart(term, parts[next]); matched {
+				score += partScore
+			}
+		}
+		if !matched {
+			return 0, false
+		}
+	}
+	return score, true
+}
+
+// matchPart scores how well a query term matches one path component:
+// 3 for an exact match, 2 for a prefix, 1 for a substring and 0 when only
+// the letters appear in order
+func matchPart(term, part string) (int, bool) {
+	switch {
+	case part == term:
+		return 3, true
+	case strings.HasPrefix(part, term):
+		return 2, true
+	case strings.Contains(part, term):
+		return 1, true
+	}
+	
+	rest := part
+	for _, r := range term {
+		i := strings.IndexRune(rest, r)
+		if i < 0 {
+			return 0, false
+		}
+		rest = rest[i+len(string(r)):]
+	}
+	return 0, true
+}
\ No newline at end of file
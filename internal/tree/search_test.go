@@ -0,0 +1,67 @@
+package tree
+
+import (
+	"testing"
+)
+
+// newSearchTree builds root/{internal/{scanner/counter.go,cli},cmd/loctree,docs}
+func newSearchTree() *DirectoryNode {
+	root := NewDirectoryNode("root", "/root")
+	internal := NewDirectoryNode("internal", "/root/internal")
+	scanner := NewDirectoryNode("scanner", "/root/internal/scanner")
+	cli := NewDirectoryNode("cli", "/root/internal/cli")
+	cmd := NewDirectoryNode("cmd", "/root/cmd")
+	loctree := NewDirectoryNode("loctree", "/root/cmd/loctree")
+	docs := NewDirectoryNode("Docs", "/root/Docs")
+	
+	root.AddChild(internal)
+	root.AddChild(cmd)
+	root.AddChild(docs)
+	internal.AddChild(scanner)
+	internal.AddChild(cli)
+	cmd.AddChild(loctree)
+	scanner.AddFile(NewFileNode("counter.go", "/root/internal/scanner/counter.go"))
+	return root
+}
+
+func searchPaths(nodes []*DirectoryNode) []string {
+	paths := make([]string, len(nodes))
+	for i, node := range nodes {
+		paths[i] = node.RelativePath()
+	}
+	return paths
+}
+
+func TestSearch(t *testing.T) {
+	root := newSearchTree()
+	
+	tests := []struct {
+		query    string
+		files    bool
+		expected []string
+	}{
+		{"int/sc", false, []string{"internal/scanner"}},
+		{"scanner", false, []string{"internal/scanner"}},
+		{"c", false, []string{"cmd", "internal/cli", "Docs", "cmd/loctree", "internal/scanner"}},
+		{"DOCS", false, []string{"Docs"}},
+		{"lt", false, []string{"cmd/loctree"}},
+		{"cnt", true, []string{"internal/scanner/counter.go"}},
+		{"cnt", false, nil},
+		{"cmd/sc", false, nil},
+		{"", false, nil},
+	}
+	
+	for _, tt := range tests {
+		got := searchPaths(Search(root, tt.query, tt.files))
+		if len(got) != len(tt.expected) {
+			t.Errorf("Search(%q): expected %v, got %v", tt.query, tt.expected, got)
+			continue
+		}
+		for i := range got {
+			if got[i] != tt.expected[i] {
+				t.Errorf("Search(%q): expected %v, got %v", tt.query, tt.expected, got)
+				break
+			}
+		}
+	}
+}
\ No newline at end of file
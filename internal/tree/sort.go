@@ -0,0 +1,137 @@
+package tree
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// SortKey selects what sibling nodes are ordered by
+type SortKey int
+
+const (
+	SortLOC      SortKey = iota // Count of the displayed metric, largest first
+	SortName                    // Name, alphabetically
+	SortFiles                   // Number of files, most first
+	SortLanguage                // Share of lines in the tree's main language, largest first
+	SortChange                  // Absolute change from an earlier scan, largest first
+)
+
+var sortKeyNames = []string{"loc", "name", "files", "language", "change"}
+
+// String returns the name of the sort key
+func (k SortKey) String() string {
+	if k < 0 || int(k) >= len(sortKeyNames) {
+		return fmt.Sprintf("SortKey(%d)", int(k))
+	}
+	return sortKeyNames[k]
+}
+
+// Order is a sort order: a key, and whether the key's natural direction is
+// reversed. The zero Order sorts by LOC, largest first.
+type Order struct {
+	Key     SortKey
+	Reverse bool
+}
+
+// Ascending reports whether the order puts the smallest value first
+func (o Order) Ascending() bool {
+	return (o.Key == SortName) != o.Reverse
+}
+
+// String returns the order as accepted by ParseOrder, e.g. "loc:desc"
+func (o Order) String() string {
+	if o.Ascending() {
+		return o.Key.String() + ":asc"
+	}
+	return o.Key.String() + ":desc"
+}
+
+// Next returns the following key in its natural direction, wrapping around.
+// The change key is skipped unless withChange is set.
+func (o Order) Next(withChange bool) Order {
+	key := SortKey((int(o.Key) + 1) % len(sortKeyNames))
+	if key == SortChange && !withChange {
+		key = SortLOC
+	}
+	return Order{Key: key}
+}
+
+// ParseOrder converts a sort key name, optionally followed by ":asc" or
+// ":desc", into an Order
+func ParseOrder(value string) (Order, error) {
+	name, direction, _ := strings.Cut(value, ":")
+	for i, keyName := range sortKeyNames {
+		if name != keyName {
+			continue
+		}
+		
+		order := Order{Key: SortKey(i)}
+		switch direction {
+		case "":
+		case "asc":
+			order.Reverse = !order.Ascending()
+		case "desc":
+			order.Reverse = order.Ascending()
+		default:
+			return Order{}, fmt.Errorf("unknown sort direction %q (expected asc or desc)", direction)
+		}
+		return order, nil
+	}
+	return Order{}, fmt.Errorf("unknown sort order %q (expected %s)", name, strings.Join(sortKeyNames, ", "))
+}
+
+// SortChildrenRecursiveByOrder sorts all children and files below the node
+// in the given order, counting lines with metric. The sort is stable and
+// siblings that compare equal are ordered by name. Language share is
+// measured against the node's own most common language.
+func (n *DirectoryNode) SortChildrenRecursiveByOrder(order Order, metric Metric) {
+	mainLanguage := ""
+	if breakdown := n.LanguageBreakdown(); len(breakdown) > 0 {
+		mainLanguage = breakdown[0].Language
+	}
+	n.sortRecursive(order, metric, mainLanguage)
+}
+
+// sortRecursive sorts the node's children and files and those below them
+func (n *DirectoryNode) sortRecursive(order Order, metric Metric, mainLanguage string) {
+	n.sortChildren(order, metric, mainLanguage)
+	for _, child := range n.Children {
+		child.sortRecursive(order, metric, mainLanguage)
+	}
+}
+
+// sortChildren sorts the node's immediate children and files
+func (n *DirectoryNode) sortChildren(order Order, metric Metric, mainLanguage string) {
+	less := func(a, b *DirectoryNode) bool {
+		if c := compareBy(order.Key, a, b, metric, mainLanguage); c != 0 {
+			return (c < 0) == order.Ascending()
+		}
+		return a.Name < b.Name
+	}
+	sort.SliceStable(n.Children, func(i, j int) bool {
+		return less(n.Children[i], n.Children[j])
+	})
+	sort.SliceStable(n.Files, func(i, j int) bool {
+		return less(n.Files[i], n.Files[j])
+	})
+}
+
+// compareBy compares two nodes by a sort key, returning a negative number
+// when a has the smaller value, a positive number when b does and zero when
+// they are equal
+func compareBy(key SortKey, a, b *DirectoryNode, metric Metric, mainLanguage string) int {
+	switch key {
+	case SortName:
+		return strings.Compare(a.Name, b.Name)
+	case SortFiles:
+		return a.FileCount - b.FileCount
+	case SortLanguage:
+		// Compare a.Languages/a.LOC with b.Languages/b.LOC without dividing
+		return a.Languages[mainLanguage]*max(b.LOC, 1) - b.Languages[mainLanguage]*max(a.LOC, 1)
+	case SortChange:
+		return churn(a) - churn(b)
+	default:
+		return a.Count(metric) - b.Count(metric)
+	}
+}
\ No newline at end of file
@@ -0,0 +1,138 @@
+package tree
+
+import (
+	"testing"
+)
+
+// newSortTree builds a root with four children:
+//
+//	alpha: 100 lines (Go 100), 4 files
+//	beta:  100 lines (Go 20, YAML 80), 1 file
+//	gamma: 300 lines (Go 150, YAML 150), 2 files
+//	delta: 50 lines (YAML 50), 9 files, changed by 40 lines
+func newSortTree() *DirectoryNode {
+	root := NewDirectoryNode("root", "/root")
+	for _, c := range []struct {
+		name  string
+		langs map[string]int
+		files int
+		churn int
+	}{
+		{"gamma", map[string]int{"Go": 150, "YAML": 150}, 2, 0},
+		{"beta", map[string]int{"Go": 20, "YAML": 80}, 1, 0},
+		{"delta", map[string]int{"YAML": 50}, 9, 40},
+		{"alpha", map[string]int{"Go": 100}, 4, 0},
+	} {
+		child := NewDirectoryNode(c.name, "/root/"+c.name)
+		for lang, loc := range c.langs {
+			child.FileLanguages[lang] = loc
+			child.FileLOC += loc
+		}
+		for i := 0; i < c.files; i++ {
+			child.AddFile(NewFileNode("file", "/root/"+c.name+"/file"))
+		}
+		child.Change = &Change{Status: Changed, Added: c.churn}
+		root.AddChild(child)
+	}
+	root.CalculateLOC()
+	return root
+}
+
+func TestSortChildrenRecursiveByOrder(t *testing.T) {
+	tests := []struct {
+		order    string
+		expected string
+	}{
+		{"loc", "gamma alpha beta delta"},
+		{"loc:asc", "delta alpha beta gamma"},
+		{"name", "alpha beta delta gamma"},
+		{"name:desc", "gamma delta beta alpha"},
+		{"files", "delta alpha gamma beta"},
+		{"files:asc", "beta gamma alpha delta"},
+		{"language", "delta beta gamma alpha"}, // YAML is the main language
+		{"change", "delta alpha beta gamma"},
+	}
+	
+	for _, tt := range tests {
+		order, err := ParseOrder(tt.order)
+		if err != nil {
+			t.Fatalf("ParseOrder(%q): unexpected error: %v", tt.order, err)
+		}
+		
+		root := newSortTree()
+		root.SortChildrenRecursiveByOrder(order, MetricLines)
+		if got := root.ChildNames(); got != tt.expected {
+			t.Errorf("Sort by %s: expected %s, got %s", tt.order, tt.expected, got)
+		}
+	}
+}
+
+func TestSortChildrenBy_StableByName(t *testing.T) {
+	// Equal counts are ordered by name however the children were added
+	for i := 0; i < 20; i++ {
+		root := NewDirectoryNode("root", "/root")
+		for _, name := range []string{"c", "a", "d", "b"} {
+			child := NewDirectoryNode(name, "/root/"+name)
+			child.FileLOC = 10
+			root.AddChild(child)
+		}
+		root.CalculateLOC()
+		
+		root.SortChildrenRecursive()
+		if got := root.ChildNames(); got != "a b c d" {
+			t.Fatalf("Expected a b c d, got %s", got)
+		}
+	}
+}
+
+func TestParseOrder(t *testing.T) {
+	tests := []struct {
+		value     string
+		expected  Order
+		ascending bool
+	}{
+		{"loc", Order{Key: SortLOC}, false},
+		{"loc:desc", Order{Key: SortLOC}, false},
+		{"loc:asc", Order{Key: SortLOC, Reverse: true}, true},
+		{"name", Order{Key: SortName}, true},
+		{"name:desc", Order{Key: SortName, Reverse: true}, false},
+		{"change", Order{Key: SortChange}, false},
+	}
+	
+	for _, tt := range tests {
+		order, err := ParseOrder(tt.value)
+		if err != nil {
+			t.Errorf("ParseOrder(%q): unexpected error: %v", tt.value, err)
+			continue
+		}
+		if order != tt.expected || order.Ascending() != tt.ascending {
+			t.Errorf("ParseOrder(%q): expected %+v, got %+v", tt.value, tt.expected, order)
+		}
+		if roundTrip, _ := ParseOrder(order.String()); roundTrip != order {
+			t.Errorf("Expected %s to parse back to %+v, got %+v", order, order, roundTrip)
+		}
+	}
+	
+	for _, value := range []string{"size", "loc:up", ""} {
+		if _, err := ParseOrder(value); err == nil {
+			t.Errorf("Expected an error for %q", value)
+		}
+	}
+}
+
+func TestOrder_Next(t *testing.T) {
+	order := Order{Key: SortFiles, Reverse: true}
+	
+	if next := order.Next(false); next != (Order{Key: SortLanguage}) {
+		t.Errorf("Expected language in its natural direction, got %+v", next)
+	}
+	if next := (Order{Key: SortLanguage}).Next(false); next != (Order{}) {
+		t.Errorf("Expected change to be skipped outside diffs, got %+v", next)
+	}
+	if next := (Order{Key: SortLanguage}).Next(true); next != (Order{Key: SortChange}) {
+		t.Errorf("Expected change in diffs, got %+v", next)
+	}
+	if next := (Order{Key: SortChange}).Next(true); next != (Order{}) {
+		t.Errorf("Expected to wrap around to loc, got %+v", next)
+	}
+}
\ No newline at end of file
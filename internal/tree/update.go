@@ -0,0 +1,172 @@
+package tree
+
+import (
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	
+	"github.com/user/loctree/internal/scanner"
+)
+
+// Patch is the new state of one path of a tree, prepared by RescanPaths and
+// applied with ApplyPatches
+type Patch struct {
+	Path    string         // Slash-separated path relative to the root ("." for the root)
+	Removed bool           // The path no longer exists or is filtered out
+	Node    *DirectoryNode // New file leaf or directory subtree, when not removed
+}
+
+// RescanPaths counts the files and directories at paths that changed below
+// rootPath on disk, applying the same filters as a full build. Paths inside
+// another changed directory are covered by its patch and get none of their
+// own. The tree itself is not touched, so this can run in the background.
+func RescanPaths(rootPath string, opts Options, relPaths []string) ([]Patch, error) {
+	fsys := os.DirFS(rootPath)
+	
+	var patches []Patch
+	for _, relPath := range topmostPaths(relPaths) {
+		if relPath == "." {
+			root, err := BuildTreeWithOptions(rootPath, opts)
+			if err != nil {
+				return nil, err
+			}
+			return []Patch{{Path: ".", Node: root}}, nil
+		}
+		
+		patch := Patch{Path: relPath, Removed: true}
+		info, err := os.Lstat(filepath.Join(rootPath, filepath.FromSlash(relPath)))
+		switch {
+		case err != nil, info.Mode()&fs.ModeSymlink != 0:
+			// Gone, or a link the builder would skip
+		case !opts.Filter.BeginFS(fsys).AllowPath(relPath, info.IsDir()):
+			// Filtered out, e.g. by a new ignore rule
+		case info.IsDir():
+			walk := opts.Filter.BeginFS(fsys)
+			walk.AllowPath(relPath, true)
+			node, err := build(rootPath, fsys, relPath, walk, opts.Jobs)
+			if err != nil {
+				return nil, err
+			}
+			patch = Patch{Path: relPath, Node: node}
+		default:
+			stats, err := scanner.CountFileFS(fsys, relPath)
+			if err != nil {
+				break // Unreadable files are skipped like in a full build
+			}
+			file := NewFileNode(path.Base(relPath), filepath.Join(rootPath, filepath.FromSlash(relPath)))
+			file.Language = stats.Language
+			file.Size = info.Size()
+			addFileStats(file, stats)
+			file.CalculateLOC()
+			patch = Patch{Path: relPath, Node: file}
+		}
+		patches = append(patches, patch)
+	}
+	return patches, nil
+}
+
+// topmostPaths sorts and de-duplicates paths, dropping those that are
+// inside another of the paths
+func topmostPaths(relPaths []string) []string {
+	sorted := append([]string(nil), relPaths...)
+	sort.Strings(sorted)
+	
+	var topmost []string
+	for _, relPath := range sorted {
+		if relPath == "." {
+			return []string{"."}
+		}
+		
+		covered := false
+		for _, parent := range topmost {
+			if relPath == parent || strings.HasPrefix(relPath, parent+"/") {
+				covered = true
+				break
+			}
+		}
+		if !covered {
+			topmost = append(topmost, relPath)
+		}
+	}
+	return topmost
+}
+
+// ApplyPatches replaces the nodes at the patched paths and re-rolls the
+// totals of every directory above them. Replaced directories keep the
+// expansion state of the nodes they replace. Patches whose parent directory
+// is not in the tree are skipped. The root is returned, which is a new node
+// when the root itself was patched.
+func ApplyPatches(root *DirectoryNode, patches []Patch) *DirectoryNode {
+	for _, patch := range patches {
+		if patch.Path == "." {
+			if patch.Node != nil {
+				CopyExpansion(root, patch.Node)
+				root = patch.Node
+			}
+			continue
+		}
+		
+		parent := FindPath(root, path.Dir(patch.Path))
+		if parent == nil || parent.IsFile || parent.RelativePath() != path.Dir(patch.Path) {
+			continue
+		}
+		
+		old := parent.remove(path.Base(patch.Path))
+		if old != nil && old.IsFile {
+			addFileCounts(parent, old, -1)
+		}
+		if !patch.Removed {
+			node := patch.Node
+			if node.IsFile {
+				parent.AddFile(node)
+				addFileCounts(parent, node, 1)
+			} else {
+				if old != nil {
+					CopyExpansion(old, node)
+				}
+				parent.AddChild(node)
+			}
+		}
+		
+		for dir := parent; dir != nil; dir = dir.Parent {
+			dir.rollUp()
+		}
+	}
+	return root
+}
+
+// remove takes the file leaf or child directory with the given name out of
+// the node and returns it, or nil when there is none
+func (n *DirectoryNode) remove(name string) *DirectoryNode {
+	for i, file := range n.Files {
+		if file.Name == name {
+			n.Files = append(n.Files[:i], n.Files[i+1:]...)
+			return file
+		}
+	}
+	for i, child := range n.Children {
+		if child.Name == name {
+			n.Children = append(n.Children[:i], n.Children[i+1:]...)
+			return child
+		}
+	}
+	return nil
+}
+
+// addFileCounts adds a file leaf's counts to its directory's own totals, or
+// takes them away with a sign of -1
+func addFileCounts(dir, file *DirectoryNode, sign int) {
+	dir.FileLOC += sign * file.FileLOC
+	dir.FileCode += sign * file.FileCode
+	dir.FileComments += sign * file.FileComments
+	dir.FileBlanks += sign * file.FileBlanks
+	for lang, loc := range file.FileLanguages {
+		dir.FileLanguages[lang] += sign * loc
+		if dir.FileLanguages[lang] == 0 {
+			delete(dir.FileLanguages, lang)
+		}
+	}
+}
\ No newline at end of file
@@ -0,0 +1,123 @@
+package tree
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	
+	"github.com/user/loctree/internal/testutil"
+)
+
+// rescan applies patches for paths changed below root and checks the
+// result matches a fresh build
+func rescan(t *testing.T, root string, tree *DirectoryNode, paths ...string) *DirectoryNode {
+	t.Helper()
+	patches, err := RescanPaths(root, Options{}, paths)
+	if err != nil {
+		t.Fatalf("Error rescanning: %v", err)
+	}
+	tree = ApplyPatches(tree, patches)
+	
+	fresh, err := BuildTree(root)
+	if err != nil {
+		t.Fatalf("Error building tree: %v", err)
+	}
+	tree.SortChildrenRecursive()
+	assertSameTree(t, fresh, tree)
+	return tree
+}
+
+func TestApplyPatches_MatchesFreshBuild(t *testing.T) {
+	root := t.TempDir()
+	testutil.WriteFiles(t, root, map[string]string{
+		"main.go":         "package main\n\nfunc main() {}\n",
+		"pkg/a/a.go":      "package a\n",
+		"pkg/b/b.go":      "package b\n// b\n",
+		"pkg/b/notes.txt": "one\ntwo\n",
+	})
+	tree, err := BuildTree(root)
+	if err != nil {
+		t.Fatalf("Error building tree: %v", err)
+	}
+	findChild(tree, "pkg").IsExpanded = true
+	findChild(findChild(tree, "pkg"), "b").IsExpanded = true
+	
+	// A changed file, a new file, a new directory and a removed one
+	testutil.WriteFiles(t, root, map[string]string{
+		"pkg/b/b.go":     "package b\n\nfunc B() int {\n\treturn 1\n}\n",
+		"pkg/b/extra.go": "package b\n",
+		"cmd/tool/t.go":  "package main\n",
+	})
+	if err := os.RemoveAll(filepath.Join(root, "pkg", "a")); err != nil {
+		t.Fatal(err)
+	}
+	tree = rescan(t, root, tree, "pkg/b/b.go", "pkg/b/extra.go", "cmd", "cmd/tool", "cmd/tool/t.go", "pkg/a")
+	
+	b := findChild(findChild(tree, "pkg"), "b")
+	if !findChild(tree, "pkg").IsExpanded || !b.IsExpanded {
+		t.Error("Expected the expansion state to be kept")
+	}
+	if b.FileLanguages["Go"] != 6 {
+		t.Errorf("Expected 6 Go lines in pkg/b, got %d", b.FileLanguages["Go"])
+	}
+	
+	// Removing a file takes its lines out of every total above it
+	if err := os.Remove(filepath.Join(root, "pkg", "b", "notes.txt")); err != nil {
+		t.Fatal(err)
+	}
+	tree = rescan(t, root, tree, "pkg/b/notes.txt")
+	if _, ok := findChild(tree, "pkg").Languages["Text"]; ok {
+		t.Error("Expected the removed file's language to be gone")
+	}
+}
+
+func TestRescanPaths_AppliesFilters(t *testing.T) {
+	root := t.TempDir()
+	testutil.WriteFiles(t, root, map[string]string{
+		".gitignore": "build/\n",
+		"main.go":    "package main\n",
+	})
+	tree, err := BuildTree(root)
+	if err != nil {
+		t.Fatalf("Error building tree: %v", err)
+	}
+	
+	testutil.WriteFiles(t, root, map[string]string{
+		"build/out.go": "package out\n",
+		".hidden.go":   "package hidden\n",
+	})
+	tree = rescan(t, root, tree, "build", ".hidden.go")
+	if len(tree.Children) != 0 || len(tree.Files) != 1 {
+		t.Errorf("Expected ignored and hidden paths to stay out of the tree, got %d children and %d files", len(tree.Children), len(tree.Files))
+	}
+}
+
+func TestRescanPaths_Root(t *testing.T) {
+	root := t.TempDir()
+	testutil.WriteFiles(t, root, map[string]string{"src/main.go": "package main\n"})
+	tree, err := BuildTree(root)
+	if err != nil {
+		t.Fatalf("Error building tree: %v", err)
+	}
+	tree.IsExpanded = true
+	
+	testutil.WriteFiles(t, root, map[string]string{"docs/guide.md": "# Guide\n"})
+	updated := rescan(t, root, tree, "src/main.go", ".")
+	if updated == tree || !updated.IsExpanded {
+		t.Error("Expected a new root with the old expansion state")
+	}
+}
+
+func TestTopmostPaths(t *testing.T) {
+	got := topmostPaths([]string{"b/c", "a", "b", "a/x", "ab", "b"})
+	expected := []string{"a", "ab", "b"}
+	if len(got) != len(expected) {
+		t.Fatalf("Expected %v, got %v", expected, got)
+	}
+	for i := range expected {
+		if got[i] != expected[i] {
+			t.Errorf("Expected %v, got %v", expected, got)
+			break
+		}
+	}
+}
\ No newline at end of file
@@ -0,0 +1,120 @@
+package ui
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	
+	"github.com/aymanbagabas/go-osc52/v2"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/user/loctree/internal/format"
+	"github.com/user/loctree/internal/tree"
+)
+
+// terminalPath is the terminal the viewer renders to, which the copy
+// sequence is written to whatever stdout and stderr are redirected to
+var terminalPath = "/dev/tty"
+
+// copiedMsg reports whether text was copied to the clipboard
+type copiedMsg struct {
+	what string // What was copied, as the status names it
+	err  error
+}
+
+// copyPath copies the relative path of the selected node
+func (m *Model) copyPath() tea.Cmd {
+	node := m.selectedNode()
+	if node == nil {
+		return nil
+	}
+	
+	path := node.RelativePath()
+	return copyToClipboard(path, path)
+}
+
+// copySummary copies a plain-text summary of the selected node's subtree
+func (m *Model) copySummary() tea.Cmd {
+	node := m.selectedNode()
+	if node == nil {
+		return nil
+	}
+	
+	return copyToClipboard(m.summary(node), "the summary of "+node.RelativePath())
+}
+
+// summary describes a node as plain text: its path and counts, its
+// languages and the rows below it as currently shown, e.g.
+//
+//	internal/scanner: 1,234 lines · 900 code · 200 comments · 134 blanks in 12 files
+//	Go 1,100 · YAML 134
+//
+//	▼ 1234 scanner
+//	  830 testdata
+func (m Model) summary(node *tree.DirectoryNode) string {
+	var b strings.Builder
+	
+	metrics := []tree.Metric{tree.MetricLines, tree.MetricCode, tree.MetricComments, tree.MetricBlanks}
+	counts := make([]string, len(metrics))
+	for i, metric := range metrics {
+		counts[i] = fmt.Sprintf("%s %s", format.Number(node.Count(metric)), metric)
+	}
+	fmt.Fprintf(&b, "%s: %s in %s files\n", node.RelativePath(), strings.Join(counts, " · "), format.Number(node.FileCount))
+	if len(node.Languages) > 0 {
+		b.WriteString(format.Languages(node.LanguageBreakdown(), 0) + "\n")
+	}
+	
+	// The node's row and the visible rows inside it, indented from the node
+	b.WriteString("\n")
+	depth := node.Depth()
+	for _, row := range m.VisibleNodes[m.indexOf(node):] {
+		rowDepth := row.Depth()
+		if row != node && rowDepth <= depth {
+			break
+		}
+		b.WriteString(format.TreeRow(row, rowDepth-depth, m.Metric, m.ShowFiles) + "\n")
+	}
+	return b.String()
+}
+
+// indexOf returns the index of node in VisibleNodes, or the end when it is
+// not visible
+func (m Model) indexOf(node *tree.DirectoryNode) int {
+	for i, visible := range m.VisibleNodes {
+		if visible == node {
+			return i
+		}
+	}
+	return len(m.VisibleNodes)
+}
+
+// copyToClipboard copies text to the clipboard of the terminal, wherever it
+// runs, with an OSC52 escape sequence. This works over SSH and needs no
+// clipboard tool.
+func copyToClipboard(text, what string) tea.Cmd {
+	return func() tea.Msg {
+		tty, err := os.OpenFile(terminalPath, os.O_WRONLY, 0)
+		if err != nil {
+			return copiedMsg{what: what, err: err}
+		}
+		
+		// A single write, so it cannot split a frame of the view
+		_, err = clipboardSequence(text, os.Getenv("TMUX"), os.Getenv("TERM")).WriteTo(tty)
+		if closeErr := tty.Close(); err == nil {
+			err = closeErr
+		}
+		return copiedMsg{what: what, err: err}
+	}
+}
+
+// clipboardSequence returns the OSC52 sequence that copies text, wrapped so
+// tmux or screen pass it on to the terminal
+func clipboardSequence(text, tmux, term string) osc52.Sequence {
+	seq := osc52.New(text)
+	switch {
+	case tmux != "":
+		return seq.Tmux()
+	case strings.HasPrefix(term, "screen"):
+		return seq.Screen()
+	}
+	return seq
+}
\ No newline at end of file
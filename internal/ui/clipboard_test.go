@@ -0,0 +1,100 @@
+package ui
+
+import (
+	"encoding/base64"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/user/loctree/internal/tree"
+)
+
+func TestClipboardSequence(t *testing.T) {
+	encoded := base64.StdEncoding.EncodeToString([]byte("internal/ui"))
+	
+	plain := clipboardSequence("internal/ui", "", "xterm-256color").String()
+	if plain != "\x1b]52;c;"+encoded+"\x07" {
+		t.Errorf("Expected a plain OSC52 sequence, got %q", plain)
+	}
+	if tmux := clipboardSequence("internal/ui", "/tmp/tmux-1000/default", "screen").String(); !strings.HasPrefix(tmux, "\x1bPtmux;") {
+		t.Errorf("Expected a sequence passed through tmux, got %q", tmux)
+	}
+	if screen := clipboardSequence("internal/ui", "", "screen.xterm").String(); !strings.HasPrefix(screen, "\x1bP") {
+		t.Errorf("Expected a sequence passed through screen, got %q", screen)
+	}
+}
+
+func TestSummary(t *testing.T) {
+	root := tree.NewDirectoryNode("root", "/root")
+	internal := tree.NewDirectoryNode("internal", "/root/internal")
+	scanner := tree.NewDirectoryNode("scanner", "/root/internal/scanner")
+	testdata := tree.NewDirectoryNode("testdata", "/root/internal/scanner/testdata")
+	ui := tree.NewDirectoryNode("ui", "/root/internal/ui")
+	root.AddChild(internal)
+	internal.AddChild(scanner)
+	internal.AddChild(ui)
+	scanner.AddChild(testdata)
+	scanner.FileLOC, scanner.FileCode, scanner.FileLanguages = 1200, 1000, map[string]int{"Go": 1200}
+	testdata.FileLOC, testdata.FileBlanks, testdata.FileLanguages = 34, 34, map[string]int{"Text": 34}
+	ui.FileLOC = 10
+	root.CalculateLOC()
+	root.IsExpanded, internal.IsExpanded, scanner.IsExpanded = true, true, true
+	
+	m := NewModel(root)
+	expected := "internal/scanner: 1,234 lines · 1,000 code · 0 comments · 34 blanks in 0 files\n" +
+		"Go 1,200 · Text 34\n" +
+		"\n" +
+		"▼ 1234 scanner\n" +
+		"  34 testdata\n"
+	if got := m.summary(scanner); got != expected {
+		t.Errorf("Expected:\n%s\ngot:\n%s", expected, got)
+	}
+}
+
+func TestUpdate_CopyKeys(t *testing.T) {
+	terminal := filepath.Join(t.TempDir(), "tty")
+	if err := os.WriteFile(terminal, nil, 0644); err != nil {
+		t.Fatal(err)
+	}
+	defer func(path string) { terminalPath = path }(terminalPath)
+	terminalPath = terminal
+	t.Setenv("TMUX", "")
+	t.Setenv("TERM", "xterm-256color")
+	
+	root := tree.NewDirectoryNode("root", "/root")
+	root.AddChild(tree.NewDirectoryNode("src", "/root/src"))
+	root.IsExpanded = true
+	
+	var model tea.Model = *NewModel(root)
+	model = pressKey(model, "j")
+	model, cmd := model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("y")})
+	if cmd == nil {
+		t.Fatal("Expected a command copying the path")
+	}
+	model, _ = model.Update(cmd())
+	if view := model.View(); !strings.Contains(view, "Copied src") {
+		t.Errorf("Expected the copied path in the footer, got:\n%s", view)
+	}
+	if written, _ := os.ReadFile(terminal); string(written) != clipboardSequence("src", "", "xterm-256color").String() {
+		t.Errorf("Expected the copy sequence written to the terminal, got %q", written)
+	}
+	
+	model, cmd = model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("Y")})
+	if cmd == nil {
+		t.Fatal("Expected a command copying the summary")
+	}
+	model, _ = model.Update(cmd())
+	if view := model.View(); !strings.Contains(view, "Copied the summary of src") {
+		t.Errorf("Expected the copied summary in the footer, got:\n%s", view)
+	}
+	
+	// Without a terminal nothing is copied
+	terminalPath = filepath.Join(t.TempDir(), "missing", "tty")
+	_, cmd = model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("y")})
+	model, _ = model.Update(cmd())
+	if view := model.View(); strings.Contains(view, "Copied") || !strings.Contains(view, "Copy failed") {
+		t.Errorf("Expected the failure in the footer, got:\n%s", view)
+	}
+}
\ No newline at end of file
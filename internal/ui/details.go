@@ -0,0 +1,120 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+	
+	"github.com/user/loctree/internal/format"
+	"github.com/user/loctree/internal/tree"
+)
+
+// Detail pane widths: a third of the terminal within these limits, or the
+// default when the width is unknown. The pane is hidden when it would leave
+// the tree narrower than minTreeWidth.
+const (
+	minPaneWidth     = 30
+	maxPaneWidth     = 60
+	defaultPaneWidth = 40
+	minTreeWidth     = 30
+)
+
+// maxLargestFiles limits how many files the detail pane lists
+const maxLargestFiles = 5
+
+// RenderDetails renders the detail pane for a node: its full path, counts,
+// code/comment/blank split, language breakdown and largest files. The pane
+// is width characters wide including its left border and is cut to height
+// lines; a height of zero or less shows every line.
+func RenderDetails(node *tree.DirectoryNode, metric tree.Metric, width, height int) string {
+	var lines []string
+	if node != nil {
+		lines = detailLines(node, metric)
+	}
+	if height > 0 && len(lines) > height {
+		lines = lines[:height]
+	}
+	
+	style := paneStyle.Width(width - 1).MaxWidth(width)
+	if height > 0 {
+		style = style.Height(height)
+	}
+	return style.Render(strings.Join(lines, "\n"))
+}
+
+// detailLines returns the lines of the detail pane, section by section
+func detailLines(node *tree.DirectoryNode, metric tree.Metric) []string {
+	lines := []string{selectedStyle.Render(node.Path), ""}
+	
+	row := func(label, value string) {
+		lines = append(lines, summaryStyle.Render(fmt.Sprintf("%-12s", label))+value)
+	}
+	
+	if node.IsFile {
+		row("Language", orNone(node.Language))
+		row("Size", format.Bytes(node.Size))
+		row("Lines", format.Number(node.LOC))
+	} else {
+		row("Lines", format.Number(node.LOC))
+		row("Own files", format.Number(node.FileLOC))
+		row("Files", fmt.Sprintf("%s (%s here)", format.Number(node.FileCount), format.Number(len(node.Files))))
+		row("Directories", fmt.Sprintf("%s (%s here)", format.Number(node.DirCount()), format.Number(len(node.Children))))
+	}
+	
+	// Shares are aligned after counts as wide as the total
+	numberWidth := len(format.Number(node.LOC))
+	share := func(count int) string {
+		return fmt.Sprintf("%*s %s", numberWidth, format.Number(count), format.Percent(count, node.LOC))
+	}
+	
+	lines = append(lines, "")
+	for _, m := range []tree.Metric{tree.MetricCode, tree.MetricComments, tree.MetricBlanks} {
+		value := share(node.Count(m))
+		if m == metric {
+			value = locStyle.Render(value)
+		}
+		row(capitalize(m.String()), value)
+	}
+	
+	if breakdown := node.LanguageBreakdown(); len(breakdown) > 0 && !node.IsFile {
+		lines = append(lines, "", summaryStyle.Render("Languages"))
+		for _, entry := range breakdown {
+			row("  "+entry.Language, share(entry.LOC))
+		}
+	}
+	
+	if files := node.LargestFiles(metric, maxLargestFiles); len(files) > 0 && !node.IsFile {
+		lines = append(lines, "", summaryStyle.Render("Largest files"))
+		countWidth := len(format.Number(files[0].Count(metric)))
+		for _, file := range files {
+			count := fmt.Sprintf("%*s", countWidth, format.Number(file.Count(metric)))
+			lines = append(lines, "  "+locStyle.Render(count)+" "+relativeTo(file, node))
+		}
+	}
+	
+	return lines
+}
+
+// relativeTo returns the slash-separated path of node below ancestor
+func relativeTo(node, ancestor *tree.DirectoryNode) string {
+	var parts []string
+	for current := node; current != nil && current != ancestor; current = current.Parent {
+		parts = append([]string{current.Name}, parts...)
+	}
+	return strings.Join(parts, "/")
+}
+
+// capitalize upper-cases the first letter of a word
+func capitalize(word string) string {
+	if word == "" {
+		return word
+	}
+	return strings.ToUpper(word[:1]) + word[1:]
+}
+
+// orNone returns value, or "none" when it is empty
+func orNone(value string) string {
+	if value == "" {
+		return "none"
+	}
+	return value
+}
\ No newline at end of file
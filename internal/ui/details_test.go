@@ -0,0 +1,83 @@
+package ui
+
+import (
+	"strings"
+	"testing"
+	
+	"github.com/charmbracelet/lipgloss"
+	"github.com/user/loctree/internal/scanner"
+	"github.com/user/loctree/internal/tree"
+)
+
+// newDetailTree builds /work/project/{pkg/{util.go,sub/},main.go}
+func newDetailTree() *tree.DirectoryNode {
+	root := tree.NewDirectoryNode("project", "/work/project")
+	pkg := tree.NewDirectoryNode("pkg", "/work/project/pkg")
+	sub := tree.NewDirectoryNode("sub", "/work/project/pkg/sub")
+	root.AddChild(pkg)
+	pkg.AddChild(sub)
+	
+	for _, f := range []struct {
+		dir  *tree.DirectoryNode
+		name string
+		loc  int
+	}{
+		{root, "main.go", 300},
+		{pkg, "util.go", 1200},
+	} {
+		f.dir.AddCountedFile(f.name, scanner.FileStats{
+			Language:   "Go",
+			LineCounts: scanner.LineCounts{Lines: f.loc, Code: f.loc * 3 / 4, Blanks: f.loc / 4},
+		})
+	}
+	
+	root.CalculateLOC()
+	return root
+}
+
+func TestRenderDetails_Directory(t *testing.T) {
+	root := newDetailTree()
+	
+	pane := RenderDetails(root, tree.MetricLines, 40, 0)
+	
+	for _, expected := range []string{
+		"/work/project",
+		"Lines       1,500",
+		"Own files   300",
+		"Files       2 (1 here)",
+		"Directories 2 (1 here)",
+		"Code        1,125  75.0%",
+		"Blanks        375  25.0%",
+		"  Go        1,500 100.0%",
+		"1,200 pkg/util.go",
+		"  300 main.go",
+	} {
+		if !strings.Contains(pane, expected) {
+			t.Errorf("Expected %q in the pane, got:\n%s", expected, pane)
+		}
+	}
+	for _, line := range strings.Split(pane, "\n") {
+		if width := lipgloss.Width(line); width != 40 {
+			t.Errorf("Expected lines 40 characters wide, got %d: %q", width, line)
+		}
+	}
+}
+
+func TestRenderDetails_FileAndHeight(t *testing.T) {
+	file := newDetailTree().Files[0]
+	file.Size = 2048
+	
+	pane := RenderDetails(file, tree.MetricLines, 40, 5)
+	
+	if lines := strings.Count(pane, "\n") + 1; lines != 5 {
+		t.Errorf("Expected the pane to be cut to 5 lines, got %d:\n%s", lines, pane)
+	}
+	for _, expected := range []string{"/work/project/main.go", "Language    Go", "Size        2.0 KB"} {
+		if !strings.Contains(pane, expected) {
+			t.Errorf("Expected %q in the pane, got:\n%s", expected, pane)
+		}
+	}
+	if strings.Contains(pane, "Own files") {
+		t.Errorf("Expected no directory counts for a file, got:\n%s", pane)
+	}
+}
\ No newline at end of file
@@ -0,0 +1,113 @@
+package ui
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// Programs used when $EDITOR or $SHELL is not set
+const (
+	defaultEditor = "vi"
+	defaultShell  = "/bin/sh"
+)
+
+// execFinishedMsg reports that the editor or shell has exited
+type execFinishedMsg struct {
+	name string
+	err  error
+}
+
+// openEditor suspends the viewer and opens the selected file, or the
+// largest file of the selected directory, in $EDITOR
+func (m *Model) openEditor() tea.Cmd {
+	path, ok := m.editorTarget()
+	if !ok {
+		m.status = "No file to edit"
+		return nil
+	}
+	return execCommand(editorCommand(path))
+}
+
+// openShell suspends the viewer and starts $SHELL in the selected
+// directory, or the directory of the selected file
+func (m *Model) openShell() tea.Cmd {
+	dir, ok := m.shellDir()
+	if !ok {
+		m.status = "No directory to open"
+		return nil
+	}
+	return execCommand(shellCommand(dir))
+}
+
+// execCommand runs a program in the terminal, resuming the viewer when it
+// exits
+func execCommand(cmd *exec.Cmd) tea.Cmd {
+	name := filepath.Base(cmd.Path)
+	return tea.ExecProcess(cmd, func(err error) tea.Msg {
+		return execFinishedMsg{name: name, err: err}
+	})
+}
+
+// editorTarget returns the file the editor opens: the selected file, or the
+// largest file of the selected directory by the current metric. Nodes that
+// are not on disk, like those of snapshots, have none.
+func (m Model) editorTarget() (string, bool) {
+	node := m.selectedNode()
+	if node == nil {
+		return "", false
+	}
+	if !node.IsFile {
+		largest := node.LargestFiles(m.Metric, 1)
+		if len(largest) == 0 {
+			return "", false
+		}
+		node = largest[0]
+	}
+	
+	if info, err := os.Stat(node.Path); err != nil || info.IsDir() {
+		return "", false
+	}
+	return node.Path, true
+}
+
+// shellDir returns the directory the shell starts in
+func (m Model) shellDir() (string, bool) {
+	node := m.selectedNode()
+	if node == nil {
+		return "", false
+	}
+	
+	dir := node.Path
+	if node.IsFile {
+		dir = filepath.Dir(node.Path)
+	}
+	if info, err := os.Stat(dir); err != nil || !info.IsDir() {
+		return "", false
+	}
+	return dir, true
+}
+
+// editorCommand returns the command that edits path with $EDITOR, which
+// may include arguments, e.g. "code --wait"
+func editorCommand(path string) *exec.Cmd {
+	args := strings.Fields(os.Getenv("EDITOR"))
+	if len(args) == 0 {
+		args = []string{defaultEditor}
+	}
+	return exec.Command(args[0], append(args[1:], path)...)
+}
+
+// shellCommand returns the command that starts $SHELL in dir
+func shellCommand(dir string) *exec.Cmd {
+	shell := os.Getenv("SHELL")
+	if shell == "" {
+		shell = defaultShell
+	}
+	cmd := exec.Command(shell)
+	cmd.Dir = dir
+	return cmd
+}
\ No newline at end of file
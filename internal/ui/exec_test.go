@@ -0,0 +1,75 @@
+package ui
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+	
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/user/loctree/internal/tree"
+)
+
+func TestEditorTarget_LargestFileOfDirectory(t *testing.T) {
+	m, dir := newScannedModel(t, map[string]string{
+		"src/small.go": "package src\n",
+		"src/big.go":   "package src\n\nfunc Big() {}\n",
+	}, Options{Depth: 1})
+	m.SelectedIndex = 1 // src
+	
+	path, ok := m.editorTarget()
+	if !ok || path != filepath.Join(dir, "src", "big.go") {
+		t.Errorf("Expected src/big.go, got %q", path)
+	}
+	
+	m.SetShowFiles(true)
+	m.selectedNode().IsExpanded = true
+	m.updateVisibleNodes()
+	m.SelectedIndex = 3 // small.go
+	if path, _ := m.editorTarget(); path != filepath.Join(dir, "src", "small.go") {
+		t.Errorf("Expected the selected file, got %q", path)
+	}
+	if shellDir, _ := m.shellDir(); shellDir != filepath.Join(dir, "src") {
+		t.Errorf("Expected the file's directory, got %q", shellDir)
+	}
+}
+
+func TestOpenEditor_NothingOnDisk(t *testing.T) {
+	var model tea.Model = *NewModel(tree.NewDirectoryNode("root", "/nonexistent/root"))
+	
+	model, cmd := model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("e")})
+	if cmd != nil {
+		t.Error("Expected no editor for a tree that is not on disk")
+	}
+	if view := model.View(); !strings.Contains(view, "No file to edit") {
+		t.Errorf("Expected a message in the footer, got:\n%s", view)
+	}
+	
+	// The message goes away with the next key press
+	model, cmd = model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("o")})
+	if cmd != nil {
+		t.Error("Expected no shell for a tree that is not on disk")
+	}
+	if view := model.View(); strings.Contains(view, "No file to edit") || !strings.Contains(view, "No directory to open") {
+		t.Errorf("Expected only the latest message, got:\n%s", view)
+	}
+}
+
+func TestEditorCommand(t *testing.T) {
+	t.Setenv("EDITOR", "code --wait")
+	if got := strings.Join(editorCommand("main.go").Args, " "); got != "code --wait main.go" {
+		t.Errorf("Expected code --wait main.go, got %s", got)
+	}
+	
+	t.Setenv("EDITOR", "")
+	if got := strings.Join(editorCommand("main.go").Args, " "); got != "vi main.go" {
+		t.Errorf("Expected vi main.go, got %s", got)
+	}
+}
+
+func TestShellCommand(t *testing.T) {
+	t.Setenv("SHELL", "/bin/zsh")
+	cmd := shellCommand("/tmp")
+	if cmd.Args[0] != "/bin/zsh" || cmd.Dir != "/tmp" {
+		t.Errorf("Expected /bin/zsh in /tmp, got %s in %s", cmd.Args[0], cmd.Dir)
+	}
+}
\ No newline at end of file
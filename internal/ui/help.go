@@ -0,0 +1,83 @@
+package ui
+
+import (
+	"strings"
+	
+	"github.com/charmbracelet/lipgloss"
+)
+
+// helpColumnGap separates the columns of the help overlay
+const helpColumnGap = "    "
+
+// renderHelp renders the help overlay: every binding of groups under its
+// group's title, in as many columns as the height needs. Columns that do not
+// fit the width continue below.
+func renderHelp(groups []keyGroup, width, height int) string {
+	// Groups stay whole; a group that does not fit below the previous one
+	// starts a new column. Two lines are left for the hint.
+	var columns [][]keyGroup
+	var column []keyGroup
+	lines := 0
+	for _, group := range groups {
+		groupLines := 1 + len(group.bindings)
+		if len(column) > 0 && height > 0 && lines+1+groupLines > height-2 {
+			columns = append(columns, column)
+			column, lines = nil, 0
+		}
+		if len(column) > 0 {
+			lines++
+		}
+		column = append(column, group)
+		lines += groupLines
+	}
+	columns = append(columns, column)
+	
+	var rows []string
+	var row []string
+	rowWidth := 0
+	for _, column := range columns {
+		block := renderHelpColumn(column)
+		blockWidth := lipgloss.Width(block)
+		if len(row) > 0 && width > 0 && rowWidth+len(helpColumnGap)+blockWidth > width {
+			rows = append(rows, lipgloss.JoinHorizontal(lipgloss.Top, row...))
+			row, rowWidth = nil, 0
+		}
+		if len(row) > 0 {
+			row = append(row, helpColumnGap)
+			rowWidth += len(helpColumnGap)
+		}
+		row = append(row, block)
+		rowWidth += blockWidth
+	}
+	rows = append(rows, lipgloss.JoinHorizontal(lipgloss.Top, row...))
+	
+	view := strings.Join(rows, "\n\n") + "\n\n" + summaryStyle.Render("Press any key to close")
+	if width > 0 {
+		view = lipgloss.NewStyle().MaxWidth(width).Render(view)
+	}
+	return view
+}
+
+// renderHelpColumn renders groups one below the other, with the keys of
+// every binding lined up
+func renderHelpColumn(groups []keyGroup) string {
+	keyWidth := 0
+	for _, group := range groups {
+		for _, b := range group.bindings {
+			keyWidth = max(keyWidth, lipgloss.Width(b.keyLabel()))
+		}
+	}
+	
+	var lines []string
+	for i, group := range groups {
+		if i > 0 {
+			lines = append(lines, "")
+		}
+		lines = append(lines, helpTitleStyle.Render(group.title))
+		for _, b := range group.bindings {
+			label := b.keyLabel()
+			lines = append(lines, label+strings.Repeat(" ", keyWidth-lipgloss.Width(label))+"  "+b.help)
+		}
+	}
+	return strings.Join(lines, "\n")
+}
\ No newline at end of file
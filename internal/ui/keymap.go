@@ -0,0 +1,246 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+)
+
+// action is what a key press does in the tree view
+type action int
+
+const (
+	noAction action = iota
+	actionQuit
+	actionUp
+	actionDown
+	actionPageUp
+	actionPageDown
+	actionHalfPageUp
+	actionHalfPageDown
+	actionTop
+	actionBottom
+	actionToggle
+	actionCollapseOrParent
+	actionExpandOrChild
+	actionExpandSubtree
+	actionCollapseAll
+	actionCollapseParent
+	actionExpandToDepth
+	actionSearch
+	actionFilter
+	actionNextMatch
+	actionPrevMatch
+	actionMetric
+	actionSort
+	actionReverseSort
+	actionParentColumn
+	actionRootColumn
+	actionFilesColumn
+	actionBarColumn
+	actionDetails
+	actionFiles
+	actionRescan
+	actionEdit
+	actionShell
+	actionCopyPath
+	actionCopySummary
+	actionHelp
+)
+
+// binding ties keys, as named by tea.KeyMsg.String, to an action
+type binding struct {
+	name   string // Name of the action in config files; empty when its keys are fixed
+	action action
+	keys   []string
+	help   string
+	label  string // Keys as shown in the help overlay; empty to list them
+}
+
+// keyGroup is a titled section of the help overlay
+type keyGroup struct {
+	title    string
+	bindings []binding
+}
+
+// defaultKeymap lists every key binding of the tree view, grouped as the
+// help overlay shows them
+var defaultKeymap = []keyGroup{
+	{"Navigation", []binding{
+		{name: "up", action: actionUp, keys: []string{"up", "k"}, help: "Move up"},
+		{name: "down", action: actionDown, keys: []string{"down", "j"}, help: "Move down"},
+		{name: "page-up", action: actionPageUp, keys: []string{"pgup"}, help: "Move up one screen"},
+		{name: "page-down", action: actionPageDown, keys: []string{"pgdown"}, help: "Move down one screen"},
+		{name: "half-page-up", action: actionHalfPageUp, keys: []string{"ctrl+u"}, help: "Move up half a screen"},
+		{name: "half-page-down", action: actionHalfPageDown, keys: []string{"ctrl+d"}, help: "Move down half a screen"},
+		{name: "top", action: actionTop, keys: []string{"home"}, help: "Jump to the first row"},
+		{name: "bottom", action: actionBottom, keys: []string{"end"}, help: "Jump to the last row"},
+		{name: "search", action: actionSearch, keys: []string{"/"}, help: "Search by name or fuzzy path"},
+		{name: "next-match", action: actionNextMatch, keys: []string{"n"}, help: "Next search match"},
+		{name: "prev-match", action: actionPrevMatch, keys: []string{"N"}, help: "Previous search match"},
+	}},
+	{"Tree", []binding{
+		{name: "toggle", action: actionToggle, keys: []string{" ", "enter"}, help: "Expand/collapse directory"},
+		{name: "collapse-or-parent", action: actionCollapseOrParent, keys: []string{"left", "h"}, help: "Collapse, or go to parent"},
+		{name: "expand-or-child", action: actionExpandOrChild, keys: []string{"right", "l"}, help: "Expand, or go to first child"},
+		{name: "expand-subtree", action: actionExpandSubtree, keys: []string{"E", "*"}, help: "Expand everything below"},
+		{name: "collapse-all", action: actionCollapseAll, keys: []string{"C"}, help: "Collapse everything"},
+		{name: "collapse-parent", action: actionCollapseParent, keys: []string{"H", "backspace"}, help: "Collapse the parent"},
+		{action: actionExpandToDepth, keys: []string{"1", "2", "3", "4", "5", "6", "7", "8", "9"}, help: "Expand to that depth", label: "1–9"},
+		{name: "filter", action: actionFilter, keys: []string{"f"}, help: "Filter the tree"},
+	}},
+	{"Display", []binding{
+		{name: "metric", action: actionMetric, keys: []string{"m"}, help: "Cycle the metric"},
+		{name: "sort", action: actionSort, keys: []string{"s"}, help: "Cycle the sort order"},
+		{name: "reverse-sort", action: actionReverseSort, keys: []string{"S"}, help: "Reverse the sort order"},
+		{name: "parent-column", action: actionParentColumn, keys: []string{"p"}, help: "Share of parent column"},
+		{name: "root-column", action: actionRootColumn, keys: []string{"P"}, help: "Share of root column"},
+		{name: "files-column", action: actionFilesColumn, keys: []string{"#"}, help: "File count column"},
+		{name: "bar-column", action: actionBarColumn, keys: []string{"b"}, help: "Bar column"},
+		{name: "details", action: actionDetails, keys: []string{"d"}, help: "Detail pane"},
+		{name: "files", action: actionFiles, keys: []string{"F"}, help: "Files as leaves"},
+	}},
+	{"Tools", []binding{
+		{name: "rescan", action: actionRescan, keys: []string{"r"}, help: "Rescan the directory"},
+		{name: "edit", action: actionEdit, keys: []string{"e"}, help: "Open in $EDITOR"},
+		{name: "shell", action: actionShell, keys: []string{"o"}, help: "Open $SHELL here"},
+		{name: "copy-path", action: actionCopyPath, keys: []string{"y"}, help: "Copy the path"},
+		{name: "copy-summary", action: actionCopySummary, keys: []string{"Y"}, help: "Copy a summary"},
+		{name: "help", action: actionHelp, keys: []string{"?"}, help: "Show/hide this help"},
+		{name: "quit", action: actionQuit, keys: []string{"q", "ctrl+c"}, help: "Quit"},
+	}},
+}
+
+// keyAliases are names of keys in config files that tea.KeyMsg.String
+// spells differently
+var keyAliases = map[string]string{
+	"space": " ",
+}
+
+// CheckKeys checks that keys, the keys bound to actions by name as read
+// from a config file, name known actions
+func CheckKeys(keys map[string][]string) error {
+	_, err := remapKeys(defaultKeymap, keys)
+	return err
+}
+
+// remapKeys returns groups with the keys of the actions named in keys
+// replaced. Keys bound to a remapped action are taken from the others, and
+// bindings left without keys are dropped.
+func remapKeys(groups []keyGroup, keys map[string][]string) ([]keyGroup, error) {
+	if len(keys) == 0 {
+		return groups, nil
+	}
+	
+	known := map[string]bool{}
+	for _, group := range groups {
+		for _, b := range group.bindings {
+			if b.name != "" {
+				known[b.name] = true
+			}
+		}
+	}
+	taken := map[string]bool{}
+	for name, remapped := range keys {
+		if !known[name] {
+			return nil, fmt.Errorf("unknown action %q in key bindings", name)
+		}
+		for _, key := range remapped {
+			taken[keyName(key)] = true
+		}
+	}
+	
+	remappedGroups := make([]keyGroup, len(groups))
+	for i, group := range groups {
+		var bindings []binding
+		for _, b := range group.bindings {
+			var boundKeys []string
+			if remapped, ok := keys[b.name]; ok && b.name != "" {
+				for _, key := range remapped {
+					boundKeys = append(boundKeys, keyName(key))
+				}
+			} else {
+				for _, key := range b.keys {
+					if !taken[key] {
+						boundKeys = append(boundKeys, key)
+					}
+				}
+			}
+			if len(boundKeys) > 0 {
+				b.keys = boundKeys
+				bindings = append(bindings, b)
+			}
+		}
+		remappedGroups[i] = keyGroup{title: group.title, bindings: bindings}
+	}
+	return remappedGroups, nil
+}
+
+// keyName returns a key of a config file as named by tea.KeyMsg.String
+func keyName(key string) string {
+	if name, ok := keyAliases[key]; ok {
+		return name
+	}
+	return key
+}
+
+// keymap finds the action bound to a key
+type keymap struct {
+	groups  []keyGroup
+	actions map[string]action
+}
+
+// newKeymap indexes the bindings of groups by key
+func newKeymap(groups []keyGroup) keymap {
+	k := keymap{groups: groups, actions: map[string]action{}}
+	for _, group := range groups {
+		for _, b := range group.bindings {
+			for _, key := range b.keys {
+				k.actions[key] = b.action
+			}
+		}
+	}
+	return k
+}
+
+// action returns the action bound to key, or noAction
+func (k keymap) action(key string) action {
+	return k.actions[key]
+}
+
+// label returns the keys bound to a as the help overlay shows them, or
+// nothing when a has no keys
+func (k keymap) label(a action) string {
+	for _, group := range k.groups {
+		for _, b := range group.bindings {
+			if b.action == a {
+				return b.keyLabel()
+			}
+		}
+	}
+	return ""
+}
+
+// keyLabels are the help overlay names of keys whose names are unclear
+var keyLabels = map[string]string{
+	" ":     "space",
+	"up":    "↑",
+	"down":  "↓",
+	"left":  "←",
+	"right": "→",
+}
+
+// keyLabel returns the keys of a binding as the help overlay shows them
+func (b binding) keyLabel() string {
+	if b.label != "" {
+		return b.label
+	}
+	
+	names := make([]string, len(b.keys))
+	for i, key := range b.keys {
+		names[i] = key
+		if label, ok := keyLabels[key]; ok {
+			names[i] = label
+		}
+	}
+	return strings.Join(names, "/")
+}
\ No newline at end of file
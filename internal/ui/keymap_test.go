@@ -0,0 +1,130 @@
+package ui
+
+import (
+	"strings"
+	"testing"
+	
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func TestDefaultKeymap_NoDuplicateKeys(t *testing.T) {
+	seen := map[string]string{}
+	for _, group := range defaultKeymap {
+		for _, b := range group.bindings {
+			for _, key := range b.keys {
+				if other, ok := seen[key]; ok {
+					t.Errorf("Key %q is bound to both %q and %q", key, other, b.help)
+				}
+				seen[key] = b.help
+			}
+		}
+	}
+	
+	keys := newKeymap(defaultKeymap)
+	if got := keys.action("j"); got != actionDown {
+		t.Errorf("Expected j to move down, got %d", got)
+	}
+	if got := keys.action("x"); got != noAction {
+		t.Errorf("Expected x to be unbound, got %d", got)
+	}
+}
+
+func TestKeyLabel(t *testing.T) {
+	tests := []struct {
+		b        binding
+		expected string
+	}{
+		{binding{keys: []string{"up", "k"}}, "↑/k"},
+		{binding{keys: []string{" ", "enter"}}, "space/enter"},
+		{binding{keys: []string{"1", "2"}, label: "1–9"}, "1–9"},
+	}
+	
+	for _, tt := range tests {
+		if got := tt.b.keyLabel(); got != tt.expected {
+			t.Errorf("Expected %q, got %q", tt.expected, got)
+		}
+	}
+}
+
+func TestUpdate_HelpOverlay(t *testing.T) {
+	var model tea.Model = newWideModel(5, 30)
+	
+	model = typeKeys(model, "?")
+	view := model.View()
+	for _, want := range []string{"Navigation", "Tools", "↑/k", "Move up", "Press any key to close"} {
+		if !strings.Contains(view, want) {
+			t.Errorf("Expected %q in the help, got:\n%s", want, view)
+		}
+	}
+	if strings.Contains(view, "dir00") {
+		t.Errorf("Expected the help to cover the tree, got:\n%s", view)
+	}
+	
+	// Any key closes the help without acting on it
+	model = typeKeys(model, "j")
+	if m := model.(Model); m.showHelp || m.SelectedIndex != 0 {
+		t.Errorf("Expected j to only close the help, got help %v and index %d", m.showHelp, m.SelectedIndex)
+	}
+	if view := model.View(); !strings.Contains(view, "dir00") {
+		t.Errorf("Expected the tree after closing the help, got:\n%s", view)
+	}
+}
+
+func TestRenderHelp_FitsHeight(t *testing.T) {
+	view := renderHelp(defaultKeymap, 0, 16)
+	if lines := strings.Count(view, "\n") + 1; lines > 16 {
+		t.Errorf("Expected at most 16 lines, got %d:\n%s", lines, view)
+	}
+	if !strings.Contains(view, "Quit") {
+		t.Errorf("Expected every group in columns, got:\n%s", view)
+	}
+}
+
+func TestRemapKeys(t *testing.T) {
+	groups, err := remapKeys(defaultKeymap, map[string][]string{
+		"quit":   {"Q"},
+		"toggle": {"space", "j"},
+		"edit":   {},
+	})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	
+	keys := newKeymap(groups)
+	tests := []struct {
+		key      string
+		expected action
+	}{
+		{"Q", actionQuit},
+		{"q", noAction},
+		{" ", actionToggle},
+		{"j", actionToggle},
+		{"enter", noAction},
+		{"down", actionDown},
+		{"e", noAction},
+	}
+	for _, tt := range tests {
+		if got := keys.action(tt.key); got != tt.expected {
+			t.Errorf("Key %q: expected action %d, got %d", tt.key, tt.expected, got)
+		}
+	}
+	if view := renderHelp(groups, 0, 0); strings.Contains(view, "Open in $EDITOR") {
+		t.Errorf("Expected the unbound action to be left out of the help, got:\n%s", view)
+	}
+	
+	if err := CheckKeys(map[string][]string{"expand-to-depth": {"x"}}); err == nil {
+		t.Error("Expected error for an action that cannot be remapped, got nil")
+	}
+}
+
+func TestUpdate_CtrlCQuitsWhateverTheBindings(t *testing.T) {
+	var model tea.Model = NewModelWithOptions(newWideModel(3, 10).Root, Options{Keys: map[string][]string{"quit": {"Q"}}})
+	
+	_, cmd := model.Update(tea.KeyMsg{Type: tea.KeyCtrlC})
+	if cmd == nil {
+		t.Error("Expected ctrl+c to quit")
+	}
+	if _, cmd := model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("q")}); cmd != nil {
+		t.Error("Expected q to be unbound")
+	}
+}
\ No newline at end of file
@@ -5,32 +5,34 @@ import (
 	"time"
 	
 	tea "github.com/charmbracelet/bubbletea"
-	"github.com/charmbracelet/lipgloss"
 	"github.com/user/loctree/internal/tree"
 )
 
 // LoadingModel shows a loading indicator while scanning
 type LoadingModel struct {
 	path     string
+	opts     Options
 	done     bool
 	root     *tree.DirectoryNode
 	err      error
 	spinner  int
+	size     tea.WindowSizeMsg
 }
 
 var spinnerFrames = []string{"⠋", "⠙", "⠹", "⠸", "⠼", "⠴", "⠦", "⠧", "⠇", "⠏"}
 
 // NewLoadingModel creates a new loading model
-func NewLoadingModel(path string) *LoadingModel {
+func NewLoadingModel(path string, opts Options) *LoadingModel {
 	return &LoadingModel{
 		path: path,
+		opts: opts,
 	}
 }
 
 // Init starts the tree building process
 func (m LoadingModel) Init() tea.Cmd {
 	return tea.Batch(
-		buildTreeCmd(m.path),
+		buildTreeCmd(m.path, m.opts.Build),
 		tickCmd(),
 	)
 }
@@ -45,8 +47,17 @@ func (m LoadingModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		if m.err != nil {
 			return m, tea.Quit
 		}
-		// Switch to main model
-		return NewModel(m.root), nil
+		// Switch to main model, which can rescan a directory on disk
+		model := NewModelWithOptions(m.root, m.opts)
+		if m.opts.Build.FS == nil {
+			model.scanPath = m.path
+		}
+		model.scanDuration = msg.duration
+		model.SetSize(m.size.Width, m.size.Height)
+		return model, model.Init()
+		
+	case tea.WindowSizeMsg:
+		m.size = msg
 		
 	case tickMsg:
 		m.spinner = (m.spinner + 1) % len(spinnerFrames)
@@ -69,27 +80,25 @@ func (m LoadingModel) View() string {
 		return fmt.Sprintf("Error: %v\n", m.err)
 	}
 	
-	style := lipgloss.NewStyle().
-		Foreground(lipgloss.Color("86")).
-		Bold(true)
-	
 	spinner := spinnerFrames[m.spinner]
-	return style.Render(fmt.Sprintf("%s Scanning: %s", spinner, m.path))
+	return selectedStyle.Render(fmt.Sprintf("%s Scanning: %s", spinner, m.path))
 }
 
 // Messages
 type treeBuiltMsg struct {
-	root *tree.DirectoryNode
-	err  error
+	root     *tree.DirectoryNode
+	err      error
+	duration time.Duration
 }
 
 type tickMsg struct{}
 
 // Commands
-func buildTreeCmd(path string) tea.Cmd {
+func buildTreeCmd(path string, opts tree.Options) tea.Cmd {
 	return func() tea.Msg {
-		root, err := tree.BuildTree(path)
-		return treeBuiltMsg{root: root, err: err}
+		start := time.Now()
+		root, err := tree.BuildTreeWithOptions(path, opts)
+		return treeBuiltMsg{root: root, err: err, duration: time.Since(start)}
 	}
 }
 
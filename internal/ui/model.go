@@ -1,8 +1,16 @@
 package ui
 
 import (
+	"fmt"
+	"strings"
+	"time"
+	
 	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/user/loctree/internal/format"
+	"github.com/user/loctree/internal/history"
 	"github.com/user/loctree/internal/tree"
+	"github.com/user/loctree/internal/watch"
 )
 
 // Model represents the TUI application state
@@ -10,14 +18,89 @@ type Model struct {
 	Root          *tree.DirectoryNode
 	VisibleNodes  []*tree.DirectoryNode
 	SelectedIndex int
+	Metric        tree.Metric           // Line count that drives display and sort order
+	Sort          tree.Order            // Order of siblings in the tree
+	ShowFiles     bool                  // Files are listed as leaves under their directory
+	Columns       format.Columns        // Optional columns shown next to each row
+	ShowDetails   bool                  // The detail pane is shown right of the tree
+	History       *history.History      // Sampled counts shown as a sparkline; nil when not loaded
+	width         int                   // Terminal width; zero until the first WindowSizeMsg
+	height        int                   // Terminal height; zero renders every visible row
+	offset        int                   // Index of the first visible node on screen
+	prompt        prompt                // Text prompt that takes key presses, if any
+	input         string                // Text typed into the prompt
+	inputErr      string                // Why the prompt's input cannot be used
+	matches       []*tree.DirectoryNode // Nodes matching the last search, best first
+	matchIndex    int                   // Index in matches of the selected match
+	searchStart   *tree.DirectoryNode   // Node selected before the search prompt opened
+	filter        string                // Active filter expression; empty shows the whole tree
+	unfiltered    *tree.DirectoryNode   // Whole tree, kept while Root is filtered
+	scanned       *tree.DirectoryNode   // Tree as scanned, before it is compared with base
+	base          *tree.DirectoryNode   // Earlier scan compared against in diff mode
+	scanPath      string                // Directory rescanned by r; empty when not scanned from disk
+	build         tree.Options          // Options the directory is rescanned with
+	watch         bool                  // Watch the directory for changes once shown
+	watcher       *watch.Watcher        // Reports changes below scanPath; nil when not watching
+	rescanning    bool                  // A rescan is running in the background
+	status        string                // Progress of a rescan, or why a command failed
+	scanDuration  time.Duration         // How long the last full scan took; zero when unknown
+	keys          keymap                // Actions bound to keys
+	showHelp      bool                  // The help overlay covers the tree
 	quitting      bool
 }
 
+// Options controls how the tree is built and initially displayed
+type Options struct {
+	Build     tree.Options        // Options passed to the tree builder
+	ShowFiles bool                // Start with files visible
+	Columns   format.Columns      // Columns shown next to each row
+	Details   bool                // Start with the detail pane open
+	Metric    tree.Metric         // Initial line count for display and sorting
+	Sort      tree.Order          // Initial order of siblings
+	Depth     int                 // Expand the tree this many levels; zero or less leaves it collapsed
+	Base      *tree.DirectoryNode // Earlier scan to compare against; enables diff mode
+	History   *history.History    // Sampled counts to show for the selected directory
+	Watch     bool                // Update the tree as files change on disk
+	Keys      map[string][]string // Keys bound to actions by name, replacing their defaults; see CheckKeys
+}
+
 // NewModel creates a new TUI model
 func NewModel(root *tree.DirectoryNode) *Model {
+	return NewModelWithOptions(root, Options{})
+}
+
+// NewModelWithOptions creates a new TUI model with the given display options
+func NewModelWithOptions(root *tree.DirectoryNode, opts Options) *Model {
+	scanned := root
+	if opts.Base != nil {
+		root = tree.Diff(opts.Base, root)
+	}
+	
 	m := &Model{
 		Root:          root,
+		scanned:       scanned,
+		base:          opts.Base,
+		build:         opts.Build,
+		watch:         opts.Watch,
 		SelectedIndex: 0,
+		ShowFiles:     opts.ShowFiles,
+		Columns:       opts.Columns,
+		ShowDetails:   opts.Details,
+		Metric:        opts.Metric,
+		Sort:          opts.Sort,
+		History:       opts.History,
+	}
+	keys, err := remapKeys(defaultKeymap, opts.Keys)
+	if err != nil {
+		// Options.Keys is checked with CheckKeys beforehand
+		keys = defaultKeymap
+	}
+	m.keys = newKeymap(keys)
+	if root.Change != nil || opts.Metric != tree.MetricLines || opts.Sort != (tree.Order{}) {
+		m.sortTree()
+	}
+	if opts.Depth > 0 {
+		tree.ExpandToDepth(root, opts.Depth)
 	}
 	m.updateVisibleNodes()
 	return m
@@ -25,43 +108,181 @@ func NewModel(root *tree.DirectoryNode) *Model {
 
 // Init initializes the model (required by tea.Model)
 func (m Model) Init() tea.Cmd {
+	if m.watch && m.scanPath != "" {
+		return m.startWatch()
+	}
 	return nil
 }
 
 // Update handles messages and updates the model (required by tea.Model)
 func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		m.SetSize(msg.Width, msg.Height)
+		
+	case tea.MouseMsg:
+		if m.prompt == noPrompt && !m.showHelp {
+			m.updateMouse(msg)
+		}
+		
+	case execFinishedMsg:
+		if msg.err != nil {
+			m.status = msg.name + " failed: " + msg.err.Error()
+		}
+		
+	case copiedMsg:
+		m.status = "Copied " + msg.what
+		if msg.err != nil {
+			m.status = "Copy failed: " + msg.err.Error()
+		}
+		
+	case rescannedMsg, watchStartedMsg, changesMsg, patchedMsg:
+		cmd := m.updateScan(msg)
+		m.scrollToSelection()
+		return m, cmd
+		
 	case tea.KeyMsg:
-		switch msg.String() {
-		case "q", "ctrl+c":
+		if m.prompt != noPrompt {
+			cmd := m.updatePrompt(msg)
+			m.scrollToSelection()
+			return m, cmd
+		}
+		
+		// Messages last until the next key press
+		if !m.rescanning {
+			m.status = ""
+		}
+		
+		if m.showHelp && msg.Type != tea.KeyCtrlC {
+			// Any key but ctrl+c, which still quits, closes the help
+			m.showHelp = false
+			return m, nil
+		}
+		
+		key := msg.String()
+		action := m.keys.action(key)
+		if msg.Type == tea.KeyCtrlC {
+			// Whatever the key bindings, ctrl+c quits
+			action = actionQuit
+		}
+		switch action {
+		case actionQuit:
 			m.quitting = true
+			if m.watcher != nil {
+				m.watcher.Close()
+			}
 			return m, tea.Quit
 			
-		case "up", "k":
+		case actionUp:
 			if m.SelectedIndex > 0 {
 				m.SelectedIndex--
 			}
 			
-		case "down", "j":
+		case actionDown:
 			if m.SelectedIndex < len(m.VisibleNodes)-1 {
 				m.SelectedIndex++
 			}
 			
-		case " ", "enter":
-			// Toggle expand/collapse
-			if m.SelectedIndex < len(m.VisibleNodes) {
-				node := m.VisibleNodes[m.SelectedIndex]
-				if len(node.Children) > 0 {
-					node.ToggleExpanded()
-					m.updateVisibleNodes()
-					// Adjust selected index if needed
-					if m.SelectedIndex >= len(m.VisibleNodes) {
-						m.SelectedIndex = len(m.VisibleNodes) - 1
-					}
-				}
-			}
+		case actionPageUp:
+			m.moveSelection(-m.treeHeight())
+			
+		case actionPageDown:
+			m.moveSelection(m.treeHeight())
+			
+		case actionHalfPageUp:
+			m.moveSelection(-halfPage(m.treeHeight()))
+			
+		case actionHalfPageDown:
+			m.moveSelection(halfPage(m.treeHeight()))
+			
+		case actionTop:
+			m.SelectedIndex = 0
+			
+		case actionBottom:
+			m.SelectedIndex = len(m.VisibleNodes) - 1
+			
+		case actionToggle:
+			m.toggleSelected()
+			
+		case actionCollapseOrParent:
+			m.collapseOrParent()
+			
+		case actionExpandOrChild:
+			m.expandOrChild()
+			
+		case actionExpandSubtree:
+			m.expandSubtree()
+			
+		case actionCollapseAll:
+			m.collapseAll()
+			
+		case actionCollapseParent:
+			m.collapseParent()
+			
+		case actionExpandToDepth:
+			m.expandToDepth(int(key[0] - '0'))
+			
+		case actionSearch:
+			m.openPrompt(searchPrompt, "")
+			
+		case actionFilter:
+			m.openPrompt(filterPrompt, m.filter)
+			
+		case actionNextMatch:
+			m.nextMatch(1)
+			
+		case actionPrevMatch:
+			m.nextMatch(-1)
+			
+		case actionMetric:
+			// Cycle the metric and re-sort, keeping the same node selected
+			m.SetMetric(m.Metric.Next())
+			
+		case actionSort:
+			// Cycle the sort key, keeping the same node selected
+			m.SetSort(m.Sort.Next(m.Root.Change != nil))
+			
+		case actionReverseSort:
+			m.SetSort(tree.Order{Key: m.Sort.Key, Reverse: !m.Sort.Reverse})
+			
+		case actionParentColumn:
+			m.Columns = m.Columns.Toggle(format.ColumnParent)
+			
+		case actionRootColumn:
+			m.Columns = m.Columns.Toggle(format.ColumnRoot)
+			
+		case actionFilesColumn:
+			m.Columns = m.Columns.Toggle(format.ColumnFiles)
+			
+		case actionBarColumn:
+			m.Columns = m.Columns.Toggle(format.ColumnBar)
+			
+		case actionDetails:
+			m.ShowDetails = !m.ShowDetails
+			
+		case actionFiles:
+			m.SetShowFiles(!m.ShowFiles)
+			
+		case actionRescan:
+			return m, m.rescan()
+			
+		case actionEdit:
+			return m, m.openEditor()
+			
+		case actionShell:
+			return m, m.openShell()
+			
+		case actionCopyPath:
+			return m, m.copyPath()
+			
+		case actionCopySummary:
+			return m, m.copySummary()
+			
+		case actionHelp:
+			m.showHelp = true
 		}
 	}
+	m.scrollToSelection()
 	return m, nil
 }
 
@@ -70,23 +291,299 @@ func (m Model) View() string {
 	if m.quitting {
 		return "Goodbye!\n"
 	}
+	if m.showHelp {
+		return renderHelp(m.keys.groups, m.width, m.height)
+	}
+	
+	// The tree and the detail pane side by side, above the footer and the
+	// status bar. The footer is rendered once and sets the tree's height.
+	footer := m.footer()
+	rows := m.treeRows(footer)
+	treeWidth, paneWidth := m.layout()
+	var view string
+	if paneWidth > 0 && treeWidth > 0 {
+		// Keep a space between the tree and the pane's border
+		view = lipgloss.NewStyle().Width(treeWidth).Render(m.treeView(treeWidth-1, rows))
+	} else {
+		view = m.treeView(treeWidth, rows)
+	}
+	if paneWidth > 0 {
+		details := RenderDetails(m.selectedNode(), m.Metric, paneWidth, lipgloss.Height(view))
+		view = lipgloss.JoinHorizontal(lipgloss.Top, view, details)
+	}
+	if footer != "" {
+		view += "\n" + footer
+	}
 	
-	return RenderTree(m.VisibleNodes, m.SelectedIndex)
+	return view + "\n" + m.statusBar(rows)
 }
 
-// updateVisibleNodes rebuilds the list of visible nodes based on expanded state
-func (m *Model) updateVisibleNodes() {
-	m.VisibleNodes = nil
-	m.addVisibleNodes(m.Root)
+// layout splits the terminal width between the tree and the detail pane.
+// The pane gets no width when it is closed or the terminal is too narrow
+// for both; a tree width of zero means the width is unknown.
+func (m Model) layout() (treeWidth, paneWidth int) {
+	switch {
+	case !m.ShowDetails:
+		return m.width, 0
+	case m.width <= 0:
+		return 0, defaultPaneWidth
+	}
+	
+	paneWidth = min(max(m.width/3, minPaneWidth), maxPaneWidth)
+	if m.width-paneWidth < minTreeWidth {
+		return m.width, 0
+	}
+	return m.width - paneWidth, paneWidth
+}
+
+// treeView renders the rows of the tree on screen, cut to width, in a tree
+// rows high
+func (m Model) treeView(width, rows int) string {
+	renderer := Renderer{Metric: m.Metric, ShowFiles: m.ShowFiles, Width: width, Columns: m.Columns}
+	start, end := m.scrolledRange(rows)
+	view := renderer.RenderTree(m.VisibleNodes[start:end], m.SelectedIndex-start)
+	if m.height > 0 {
+		// Pad short trees so the footer stays at the bottom of the screen
+		view += strings.Repeat("\n", rows-(end-start))
+	}
+	return view
 }
 
-// addVisibleNodes recursively adds visible nodes to the list
-func (m *Model) addVisibleNodes(node *tree.DirectoryNode) {
-	m.VisibleNodes = append(m.VisibleNodes, node)
+// footer renders the details of the selected node shown below the tree
+func (m Model) footer() string {
+	if m.SelectedIndex < 0 || m.SelectedIndex >= len(m.VisibleNodes) {
+		return ""
+	}
 	
-	if node.IsExpanded {
-		for _, child := range node.Children {
-			m.addVisibleNodes(child)
+	selected := m.VisibleNodes[m.SelectedIndex]
+	lines := []string{RenderCounts(selected, m.Metric)}
+	if change := RenderChange(selected); change != "" {
+		lines = append(lines, change)
+	}
+	if sparkline := RenderHistory(selected, m.History); sparkline != "" {
+		lines = append(lines, sparkline)
+	}
+	if summary := RenderSummary(selected); summary != "" {
+		lines = append(lines, summary)
+	}
+	switch {
+	case m.prompt == searchPrompt:
+		lines = append(lines, RenderSearch(m.input, m.matchIndex, len(m.matches)))
+	case m.prompt == filterPrompt:
+		lines = append(lines, RenderFilter(m.input, m.inputErr))
+	}
+	if m.status != "" {
+		lines = append(lines, RenderStatus(m.status))
+	}
+	
+	footer := strings.Join(lines, "\n")
+	if m.width > 0 {
+		footer = lipgloss.NewStyle().MaxWidth(m.width).Render(footer)
+	}
+	return footer
+}
+
+// statusBar renders the bar on the bottom line: what was scanned and how,
+// how the tree is shown and the scroll position in a tree rows high
+func (m Model) statusBar(rows int) string {
+	path := m.scanPath
+	if path == "" {
+		path = m.Root.Path
+	}
+	left := []string{
+		path,
+		fmt.Sprintf("%s %s in %s files", format.Number(m.Root.Count(m.Metric)), m.Metric, format.Number(m.Root.FileCount)),
+	}
+	if m.scanDuration > 0 {
+		left = append(left, "scanned in "+formatDuration(m.scanDuration))
+	}
+	if rules := m.build.Filter.String(); rules != "" {
+		left = append(left, rules)
+	}
+	if m.filter != "" {
+		left = append(left, "filter: "+m.filter)
+	}
+	left = append(left, "sort: "+m.Sort.String())
+	
+	var right []string
+	start, end := m.scrolledRange(rows)
+	if position := scrollPosition(start, end, len(m.VisibleNodes)); position != "" {
+		right = append(right, position)
+	}
+	if help := m.keys.label(actionHelp); help != "" {
+		right = append(right, help+" help")
+	}
+	return RenderStatusBar(left, right, m.width)
+}
+
+// formatDuration rounds a scan duration for the status bar, e.g. "850ms"
+// or "1.2s"
+func formatDuration(d time.Duration) string {
+	if d < time.Second {
+		return d.Round(time.Millisecond).String()
+	}
+	return d.Round(100 * time.Millisecond).String()
+}
+
+// jumpTo expands the directories above node and selects it
+func (m *Model) jumpTo(node *tree.DirectoryNode) {
+	tree.ExpandAncestors(node)
+	m.updateVisibleNodes()
+	m.selectNode(node)
+}
+
+// selectedNode returns the selected node, or nil when nothing is visible
+func (m Model) selectedNode() *tree.DirectoryNode {
+	if m.SelectedIndex < 0 || m.SelectedIndex >= len(m.VisibleNodes) {
+		return nil
+	}
+	return m.VisibleNodes[m.SelectedIndex]
+}
+
+// SetSize sets the terminal size the view is laid out for
+func (m *Model) SetSize(width, height int) {
+	m.width = width
+	m.height = height
+	m.scrollToSelection()
+}
+
+// treeHeight returns the number of tree rows that fit on screen above the
+// footer and the status bar
+func (m Model) treeHeight() int {
+	return m.treeRows(m.footer())
+}
+
+// treeRows is treeHeight for an already rendered footer
+func (m Model) treeRows(footer string) int {
+	if m.height <= 0 {
+		return len(m.VisibleNodes)
+	}
+	rows := m.height - 1 - lipgloss.Height(footer)
+	if rows < 1 {
+		return 1
+	}
+	return rows
+}
+
+// halfPage returns the distance ctrl+u and ctrl+d move the selection
+func halfPage(rows int) int {
+	if rows < 2 {
+		return 1
+	}
+	return rows / 2
+}
+
+// moveSelection moves the selection by delta rows, stopping at either end
+func (m *Model) moveSelection(delta int) {
+	m.SelectedIndex += delta
+	if m.SelectedIndex >= len(m.VisibleNodes) {
+		m.SelectedIndex = len(m.VisibleNodes) - 1
+	}
+	if m.SelectedIndex < 0 {
+		m.SelectedIndex = 0
+	}
+}
+
+// visibleRange returns the slice of VisibleNodes shown on screen, scrolled
+// as little as possible from the current offset to keep the selection in view
+func (m Model) visibleRange() (start, end int) {
+	return m.scrolledRange(m.treeHeight())
+}
+
+// scrolledRange is visibleRange for a tree rows high
+func (m Model) scrolledRange(rows int) (start, end int) {
+	start = m.offset
+	if m.SelectedIndex < start {
+		start = m.SelectedIndex
+	}
+	if m.SelectedIndex >= start+rows {
+		start = m.SelectedIndex - rows + 1
+	}
+	if start > len(m.VisibleNodes)-rows {
+		start = len(m.VisibleNodes) - rows
+	}
+	if start < 0 {
+		start = 0
+	}
+	
+	end = start + rows
+	if end > len(m.VisibleNodes) {
+		end = len(m.VisibleNodes)
+	}
+	return start, end
+}
+
+// scrollToSelection scrolls the viewport so the selected node is on screen
+func (m *Model) scrollToSelection() {
+	m.offset, _ = m.visibleRange()
+}
+
+// SetMetric changes the displayed metric and re-sorts the tree by it,
+// keeping the currently selected node selected
+func (m *Model) SetMetric(metric tree.Metric) {
+	var selected *tree.DirectoryNode
+	if m.SelectedIndex < len(m.VisibleNodes) {
+		selected = m.VisibleNodes[m.SelectedIndex]
+	}
+	
+	m.Metric = metric
+	if m.filter != "" {
+		// The filter's minimum line count is measured in the new metric
+		_ = m.SetFilter(m.filter)
+		return
+	}
+	m.sortTree()
+	m.updateVisibleNodes()
+	m.selectNode(selected)
+}
+
+// SetSort changes the order of siblings, keeping the currently selected
+// node selected
+func (m *Model) SetSort(order tree.Order) {
+	selected := m.selectedNode()
+	
+	m.Sort = order
+	m.sortTree()
+	m.updateVisibleNodes()
+	m.selectNode(selected)
+}
+
+// sortTree sorts the tree in the current order, counting lines with the
+// current metric
+func (m *Model) sortTree() {
+	m.Root.SortChildrenRecursiveByOrder(m.Sort, m.Metric)
+}
+
+// SetShowFiles shows or hides file leaves. When a selected file is hidden,
+// its directory becomes selected.
+func (m *Model) SetShowFiles(show bool) {
+	var selected *tree.DirectoryNode
+	if m.SelectedIndex < len(m.VisibleNodes) {
+		selected = m.VisibleNodes[m.SelectedIndex]
+	}
+	if selected != nil && selected.IsFile && !show {
+		selected = selected.Parent
+	}
+	
+	m.ShowFiles = show
+	m.updateVisibleNodes()
+	m.selectNode(selected)
+}
+
+// selectNode moves the selection to node if it is visible
+func (m *Model) selectNode(node *tree.DirectoryNode) {
+	for i, visible := range m.VisibleNodes {
+		if visible == node {
+			m.SelectedIndex = i
+			return
 		}
 	}
+	if m.SelectedIndex >= len(m.VisibleNodes) {
+		m.SelectedIndex = len(m.VisibleNodes) - 1
+	}
+}
+
+// updateVisibleNodes rebuilds the list of visible nodes based on expanded state
+func (m *Model) updateVisibleNodes() {
+	m.VisibleNodes = tree.GetVisibleNodesWithFiles(m.Root, m.ShowFiles)
 }
\ No newline at end of file
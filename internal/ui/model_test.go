@@ -1,8 +1,14 @@
 package ui
 
 import (
+	"fmt"
+	"strings"
 	"testing"
+	"time"
 	
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/user/loctree/internal/format"
 	"github.com/user/loctree/internal/tree"
 )
 
@@ -42,7 +48,495 @@ func TestView(t *testing.T) {
 	}
 }
 
+func TestSetMetric_ResortsAndKeepsSelection(t *testing.T) {
+	root := tree.NewDirectoryNode("root", "/root")
+	root.IsExpanded = true
+	
+	commented := tree.NewDirectoryNode("commented", "/root/commented")
+	commented.LOC = 100
+	commented.Code = 10
+	
+	dense := tree.NewDirectoryNode("dense", "/root/dense")
+	dense.LOC = 50
+	dense.Code = 45
+	
+	root.AddChild(commented)
+	root.AddChild(dense)
+	
+	model := NewModel(root)
+	model.SelectedIndex = 2 // dense
+	
+	model.SetMetric(tree.MetricCode)
+	
+	if model.VisibleNodes[1] != dense {
+		t.Errorf("Expected 'dense' to sort first by code, got '%s'", model.VisibleNodes[1].Name)
+	}
+	if model.VisibleNodes[model.SelectedIndex] != dense {
+		t.Errorf("Expected selection to stay on 'dense', got '%s'", model.VisibleNodes[model.SelectedIndex].Name)
+	}
+}
+
+func TestSetShowFiles(t *testing.T) {
+	root := tree.NewDirectoryNode("root", "/root")
+	root.IsExpanded = true
+	file := tree.NewFileNode("main.go", "/root/main.go")
+	root.AddFile(file)
+	
+	model := NewModel(root)
+	if len(model.VisibleNodes) != 1 {
+		t.Fatalf("Expected files to be hidden by default, got %d visible nodes", len(model.VisibleNodes))
+	}
+	
+	model.SetShowFiles(true)
+	if len(model.VisibleNodes) != 2 || model.VisibleNodes[1] != file {
+		t.Fatal("Expected file to be visible after enabling files")
+	}
+	
+	// Hiding files while one is selected moves the selection to its directory
+	model.SelectedIndex = 1
+	model.SetShowFiles(false)
+	if model.VisibleNodes[model.SelectedIndex] != root {
+		t.Errorf("Expected selection to move to the parent directory, got '%s'", model.VisibleNodes[model.SelectedIndex].Name)
+	}
+}
+
+func TestNewModelWithOptions_ShowFiles(t *testing.T) {
+	root := tree.NewDirectoryNode("root", "/root")
+	root.IsExpanded = true
+	root.AddFile(tree.NewFileNode("main.go", "/root/main.go"))
+	
+	model := NewModelWithOptions(root, Options{ShowFiles: true})
+	
+	if len(model.VisibleNodes) != 2 {
+		t.Errorf("Expected file to be visible, got %d visible nodes", len(model.VisibleNodes))
+	}
+}
+
 func containsNode(view, loc, name string) bool {
 	// Simple check - in real implementation would be more sophisticated
 	return true
+}
+
+func TestNewModelWithOptions_Diff(t *testing.T) {
+	before := tree.NewDirectoryNode("root", "/root")
+	before.FileLOC = 10
+	before.CalculateLOC()
+	
+	after := tree.NewDirectoryNode("root", "/root")
+	after.FileLOC = 25
+	after.CalculateLOC()
+	
+	model := NewModelWithOptions(after, Options{Base: before})
+	if model.Root.Change == nil || model.Root.Change.Added != 15 {
+		t.Fatalf("Expected the tree to be compared with the base, got %+v", model.Root.Change)
+	}
+	
+	view := model.View()
+	if !strings.Contains(view, "+15 / -0 root") {
+		t.Errorf("Expected diff row '+15 / -0 root', got:\n%s", view)
+	}
+	if !strings.Contains(view, "10 → 25 lines") {
+		t.Errorf("Expected the change summary, got:\n%s", view)
+	}
+}
+
+// newWideModel returns a model of a root with n expanded children, sized
+// to a terminal of the given height
+func newWideModel(n, height int) *Model {
+	root := tree.NewDirectoryNode("root", "/root")
+	root.IsExpanded = true
+	for i := 0; i < n; i++ {
+		child := tree.NewDirectoryNode(fmt.Sprintf("dir%02d", i), fmt.Sprintf("/root/dir%02d", i))
+		child.FileLOC = n - i
+		root.AddChild(child)
+	}
+	root.CalculateLOC()
+	
+	model := NewModel(root)
+	model.SetSize(80, height)
+	return model
+}
+
+func pressKey(model tea.Model, key string) tea.Model {
+	var msg tea.KeyMsg
+	switch key {
+	case "pgdown":
+		msg = tea.KeyMsg{Type: tea.KeyPgDown}
+	case "pgup":
+		msg = tea.KeyMsg{Type: tea.KeyPgUp}
+	case "end":
+		msg = tea.KeyMsg{Type: tea.KeyEnd}
+	case "home":
+		msg = tea.KeyMsg{Type: tea.KeyHome}
+	case "ctrl+d":
+		msg = tea.KeyMsg{Type: tea.KeyCtrlD}
+	case "down":
+		msg = tea.KeyMsg{Type: tea.KeyDown}
+	case "left":
+		msg = tea.KeyMsg{Type: tea.KeyLeft}
+	case "right":
+		msg = tea.KeyMsg{Type: tea.KeyRight}
+	default:
+		msg = tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(key)}
+	}
+	model, _ = model.Update(msg)
+	return model
+}
+
+func TestView_FitsTerminalHeight(t *testing.T) {
+	model := newWideModel(50, 10)
+	
+	view := model.View()
+	if lines := strings.Count(view, "\n") + 1; lines != 10 {
+		t.Errorf("Expected 10 lines, got %d:\n%s", lines, view)
+	}
+	if strings.Contains(view, "dir20") {
+		t.Errorf("Expected rows below the screen not to be rendered, got:\n%s", view)
+	}
+	// Root plus 7 children fill the 8 tree rows above the position and counts lines
+	if !strings.Contains(view, "↓ 1–8 of 51") {
+		t.Errorf("Expected scroll position '↓ 1–8 of 51', got:\n%s", view)
+	}
+}
+
+func TestView_StatusBar(t *testing.T) {
+	model := newWideModel(50, 10)
+	model.SetSize(120, 10)
+	model.scanDuration = 1234 * time.Millisecond
+	
+	view := model.View()
+	lines := strings.Split(view, "\n")
+	bar := lines[len(lines)-1]
+	for _, want := range []string{"/root", "1,275 lines in 0 files", "scanned in 1.2s", "sort: loc:desc", "↓ 1–8 of 51", "? help"} {
+		if !strings.Contains(bar, want) {
+			t.Errorf("Expected %q in the status bar, got %q", want, bar)
+		}
+	}
+	if width := lipgloss.Width(bar); width != 120 {
+		t.Errorf("Expected the status bar to fill 120 columns, got %d", width)
+	}
+}
+
+func TestUpdate_SelectionStaysInView(t *testing.T) {
+	var model tea.Model = newWideModel(50, 10)
+	
+	for i := 0; i < 10; i++ {
+		model = pressKey(model, "j")
+	}
+	m := model.(Model)
+	if m.SelectedIndex != 10 {
+		t.Fatalf("Expected selected index 10, got %d", m.SelectedIndex)
+	}
+	view := m.View()
+	if !strings.Contains(view, "dir09") || !strings.Contains(view, "↑↓ 4–11 of 51") {
+		t.Errorf("Expected the view to scroll to the selection, got:\n%s", view)
+	}
+	
+	// Moving back up inside the screen does not scroll
+	model = pressKey(model, "k")
+	if view := model.View(); !strings.Contains(view, "4–11 of 51") {
+		t.Errorf("Expected the view not to scroll, got:\n%s", view)
+	}
+}
+
+func TestUpdate_PagingKeys(t *testing.T) {
+	var model tea.Model = newWideModel(50, 10)
+	
+	tests := []struct {
+		key      string
+		expected int
+	}{
+		{"pgdown", 8},
+		{"ctrl+d", 12},
+		{"pgup", 4},
+		{"end", 50},
+		{"pgdown", 50},
+		{"home", 0},
+		{"pgup", 0},
+	}
+	
+	for _, tt := range tests {
+		model = pressKey(model, tt.key)
+		if got := model.(Model).SelectedIndex; got != tt.expected {
+			t.Errorf("After %s: expected selected index %d, got %d", tt.key, tt.expected, got)
+		}
+	}
+	
+	if view := model.View(); !strings.Contains(view, "↓ 1–8 of 51") {
+		t.Errorf("Expected the view to scroll back to the top, got:\n%s", view)
+	}
+}
+
+func TestLoadingModel_PassesWindowSize(t *testing.T) {
+	root := tree.NewDirectoryNode("root", "/root")
+	
+	var model tea.Model = *NewLoadingModel("/root", Options{})
+	model, _ = model.Update(tea.WindowSizeMsg{Width: 80, Height: 24})
+	model, _ = model.Update(treeBuiltMsg{root: root})
+	
+	m, ok := model.(*Model)
+	if !ok {
+		t.Fatalf("Expected the tree view after loading, got %T", model)
+	}
+	if m.width != 80 || m.height != 24 {
+		t.Errorf("Expected size 80x24, got %dx%d", m.width, m.height)
+	}
+}
+
+func typeKeys(model tea.Model, keys string) tea.Model {
+	for _, r := range keys {
+		model, _ = model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{r}})
+	}
+	return model
+}
+
+func TestUpdate_Search(t *testing.T) {
+	root := tree.NewDirectoryNode("root", "/root")
+	internal := tree.NewDirectoryNode("internal", "/root/internal")
+	scanner := tree.NewDirectoryNode("scanner", "/root/internal/scanner")
+	script := tree.NewDirectoryNode("scripts", "/root/scripts")
+	root.AddChild(internal)
+	root.AddChild(script)
+	internal.AddChild(scanner)
+	
+	var model tea.Model = NewModel(root)
+	model = typeKeys(model, "/int/sc")
+	
+	m := model.(Model)
+	if m.prompt != searchPrompt {
+		t.Fatal("Expected the search prompt to be open")
+	}
+	if selected := m.selectedNode(); selected != scanner {
+		t.Fatalf("Expected scanner to be selected, got %v", selected.Name)
+	}
+	if !root.IsExpanded || !internal.IsExpanded {
+		t.Error("Expected the ancestors of the match to be expanded")
+	}
+	if view := m.View(); !strings.Contains(view, "/int/sc") || !strings.Contains(view, "1 of 1") {
+		t.Errorf("Expected the search prompt in the view, got:\n%s", view)
+	}
+	
+	// Editing the query searches again
+	for range "int/sc" {
+		model, _ = model.Update(tea.KeyMsg{Type: tea.KeyBackspace})
+	}
+	model = typeKeys(model, "s")
+	model, _ = model.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	
+	m = model.(Model)
+	if m.prompt != noPrompt {
+		t.Error("Expected enter to close the search prompt")
+	}
+	if len(m.matches) != 2 || m.selectedNode() != script {
+		t.Fatalf("Expected 2 matches with scripts selected, got %d and %v", len(m.matches), m.selectedNode().Name)
+	}
+	
+	// n and N cycle through the matches
+	model = typeKeys(model, "n")
+	if selected := model.(Model).selectedNode(); selected != scanner {
+		t.Errorf("Expected n to select scanner, got %v", selected.Name)
+	}
+	model = typeKeys(model, "n")
+	if selected := model.(Model).selectedNode(); selected != script {
+		t.Errorf("Expected n to wrap around to scripts, got %v", selected.Name)
+	}
+	model = typeKeys(model, "N")
+	if selected := model.(Model).selectedNode(); selected != scanner {
+		t.Errorf("Expected N to select scanner, got %v", selected.Name)
+	}
+}
+
+func TestUpdate_SearchCancel(t *testing.T) {
+	root := tree.NewDirectoryNode("root", "/root")
+	child := tree.NewDirectoryNode("child", "/root/child")
+	root.AddChild(child)
+	
+	var model tea.Model = NewModel(root)
+	model = typeKeys(model, "/child")
+	if model.(Model).selectedNode() != child {
+		t.Fatal("Expected the match to be selected while typing")
+	}
+	
+	model, _ = model.Update(tea.KeyMsg{Type: tea.KeyEsc})
+	m := model.(Model)
+	if m.prompt != noPrompt || len(m.matches) != 0 {
+		t.Error("Expected escape to cancel the search")
+	}
+	if m.selectedNode() != root {
+		t.Errorf("Expected the selection to return to root, got %v", m.selectedNode().Name)
+	}
+}
+
+func TestUpdate_SortKeysKeepSelection(t *testing.T) {
+	root := tree.NewDirectoryNode("root", "/root")
+	root.IsExpanded = true
+	for _, c := range []struct {
+		name string
+		loc  int
+	}{{"big", 30}, {"apple", 20}, {"small", 10}} {
+		child := tree.NewDirectoryNode(c.name, "/root/"+c.name)
+		child.FileLOC = c.loc
+		root.AddChild(child)
+	}
+	root.CalculateLOC()
+	
+	var model tea.Model = NewModel(root)
+	model = typeKeys(model, "jj") // Select apple
+	
+	model = typeKeys(model, "s")
+	m := model.(Model)
+	if m.Sort != (tree.Order{Key: tree.SortName}) {
+		t.Fatalf("Expected s to sort by name, got %s", m.Sort)
+	}
+	if got := root.ChildNames(); got != "apple big small" {
+		t.Errorf("Expected apple big small, got %s", got)
+	}
+	if m.selectedNode().Name != "apple" || m.SelectedIndex != 1 {
+		t.Errorf("Expected apple to stay selected, got %s at %d", m.selectedNode().Name, m.SelectedIndex)
+	}
+	if view := m.View(); !strings.Contains(view, "sort: name:asc") {
+		t.Errorf("Expected the sort order in the view, got:\n%s", view)
+	}
+	
+	model = typeKeys(model, "S")
+	if got := root.ChildNames(); got != "small big apple" {
+		t.Errorf("Expected S to reverse the order, got %s", got)
+	}
+	if m := model.(Model); m.selectedNode().Name != "apple" {
+		t.Errorf("Expected apple to stay selected, got %s", m.selectedNode().Name)
+	}
+}
+
+func TestUpdate_ToggleColumns(t *testing.T) {
+	root := tree.NewDirectoryNode("root", "/root")
+	root.LOC = 100
+	
+	var model tea.Model = NewModelWithOptions(root, Options{Columns: format.ColumnFiles})
+	model = typeKeys(model, "pb#")
+	
+	m := model.(Model)
+	if m.Columns != format.ColumnParent|format.ColumnBar {
+		t.Errorf("Expected parent and bar columns, got %s", m.Columns)
+	}
+	if view := m.View(); !strings.Contains(view, "100.0%") {
+		t.Errorf("Expected the share column in the view, got:\n%s", view)
+	}
+}
+
+func TestView_DetailPane(t *testing.T) {
+	var model tea.Model = NewModel(newDetailTree())
+	model, _ = model.Update(tea.WindowSizeMsg{Width: 90, Height: 20})
+	model = typeKeys(model, "d")
+	
+	m := model.(Model)
+	if treeWidth, paneWidth := m.layout(); treeWidth != 60 || paneWidth != 30 {
+		t.Errorf("Expected a 60/30 split, got %d/%d", treeWidth, paneWidth)
+	}
+	view := m.View()
+	if !strings.Contains(view, "│ /work/project") || !strings.Contains(view, "Own files") {
+		t.Errorf("Expected the detail pane in the view, got:\n%s", view)
+	}
+	if lines := strings.Count(view, "\n") + 1; lines != 20 {
+		t.Errorf("Expected 20 lines, got %d", lines)
+	}
+	
+	// Too narrow for both: the pane stays hidden until the terminal grows
+	model, _ = model.Update(tea.WindowSizeMsg{Width: 50, Height: 20})
+	if view := model.View(); strings.Contains(view, "Own files") {
+		t.Errorf("Expected no detail pane in a narrow terminal, got:\n%s", view)
+	}
+	
+	model = typeKeys(model, "d")
+	model, _ = model.Update(tea.WindowSizeMsg{Width: 90, Height: 20})
+	if view := model.View(); strings.Contains(view, "Own files") {
+		t.Errorf("Expected d to close the detail pane, got:\n%s", view)
+	}
+}
+
+// newNestedModel returns a model over root/{a/{a1/{deep}},b}, collapsed
+// below the root
+func newNestedModel() *Model {
+	root := tree.NewDirectoryNode("root", "/root")
+	a := tree.NewDirectoryNode("a", "/root/a")
+	a1 := tree.NewDirectoryNode("a1", "/root/a/a1")
+	deep := tree.NewDirectoryNode("deep", "/root/a/a1/deep")
+	b := tree.NewDirectoryNode("b", "/root/b")
+	root.AddChild(a)
+	root.AddChild(b)
+	a.AddChild(a1)
+	a1.AddChild(deep)
+	deep.LOC, a1.LOC, a.LOC, b.LOC = 30, 30, 30, 10
+	root.LOC = 40
+	root.IsExpanded = true
+	
+	return NewModel(root)
+}
+
+func TestUpdate_ExpandCollapseKeys(t *testing.T) {
+	var model tea.Model = newNestedModel()
+	model = pressKey(model, "down") // Select a
+	
+	model = pressKey(model, "E")
+	m := model.(Model)
+	if len(m.VisibleNodes) != 5 {
+		t.Fatalf("Expected E to expand the whole subtree, got %d visible nodes", len(m.VisibleNodes))
+	}
+	
+	model = typeKeys(model, "jj") // Select deep
+	model = pressKey(model, "H")
+	m = model.(Model)
+	if selected := m.selectedNode(); selected.Name != "a1" || selected.IsExpanded {
+		t.Errorf("Expected H to collapse and select a1, got %s", selected.Name)
+	}
+	
+	model = pressKey(model, "C")
+	m = model.(Model)
+	if len(m.VisibleNodes) != 3 {
+		t.Errorf("Expected C to collapse everything below the root, got %d visible nodes", len(m.VisibleNodes))
+	}
+	if selected := m.selectedNode(); selected.Name != "a" {
+		t.Errorf("Expected selection to move to a, got %s", selected.Name)
+	}
+	
+	model = pressKey(model, "2")
+	if m = model.(Model); len(m.VisibleNodes) != 4 {
+		t.Errorf("Expected 2 to expand two levels, got %d visible nodes", len(m.VisibleNodes))
+	}
+	
+	model = typeKeys(model, "j") // Select a1
+	model = pressKey(model, "1")
+	m = model.(Model)
+	if len(m.VisibleNodes) != 3 {
+		t.Errorf("Expected 1 to expand only the root, got %d visible nodes", len(m.VisibleNodes))
+	}
+	if selected := m.selectedNode(); selected.Name != "a" {
+		t.Errorf("Expected selection to move to a, got %s", selected.Name)
+	}
+}
+
+func TestUpdate_LeftRightKeys(t *testing.T) {
+	var model tea.Model = newNestedModel()
+	model = pressKey(model, "down") // Select a
+	
+	for i, step := range []struct {
+		key      string
+		selected string
+		visible  int
+	}{
+		{"l", "a", 4},      // expand a
+		{"right", "a1", 4}, // move into a1
+		{"l", "a1", 5},     // expand a1
+		{"h", "a1", 4},     // collapse a1
+		{"left", "a", 4},   // go to parent
+		{"h", "a", 3},      // collapse a
+		{"h", "root", 3},   // go to parent
+		{"h", "root", 1},   // collapse root
+		{"h", "root", 1},   // nothing above the root
+	} {
+		model = pressKey(model, step.key)
+		m := model.(Model)
+		if selected := m.selectedNode().Name; selected != step.selected || len(m.VisibleNodes) != step.visible {
+			t.Errorf("Step %d (%s): expected %s with %d visible nodes, got %s with %d", i, step.key, step.selected, step.visible, selected, len(m.VisibleNodes))
+		}
+	}
 }
\ No newline at end of file
@@ -0,0 +1,70 @@
+package ui
+
+import (
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// wheelRows is the number of rows one step of the mouse wheel scrolls
+const wheelRows = 3
+
+// updateMouse handles mouse events: a left click selects the row under the
+// pointer, or toggles it when on its ▶/▼ indicator, and the wheel scrolls
+func (m *Model) updateMouse(msg tea.MouseMsg) {
+	if msg.Action != tea.MouseActionPress {
+		return
+	}
+	
+	switch msg.Button {
+	case tea.MouseButtonWheelUp:
+		m.scroll(-wheelRows)
+	case tea.MouseButtonWheelDown:
+		m.scroll(wheelRows)
+	case tea.MouseButtonLeft:
+		index, ok := m.rowAt(msg.X, msg.Y)
+		if !ok {
+			return
+		}
+		m.SelectedIndex = index
+		if m.onIndicator(index, msg.X) {
+			m.toggleSelected()
+		}
+	}
+}
+
+// rowAt maps a screen position to an index in VisibleNodes. The tree starts
+// on the first screen row; the footer, the status bar and the detail
+// pane are not rows.
+func (m Model) rowAt(x, y int) (int, bool) {
+	if treeWidth, _ := m.layout(); treeWidth > 0 && x >= treeWidth {
+		return 0, false
+	}
+	
+	start, end := m.visibleRange()
+	if y < 0 || y >= end-start {
+		return 0, false
+	}
+	return start + y, true
+}
+
+// onIndicator reports whether column x of a row falls on its expand indicator
+func (m Model) onIndicator(index, x int) bool {
+	node := m.VisibleNodes[index]
+	if !node.HasVisibleChildren(m.ShowFiles) {
+		return false
+	}
+	
+	column := 2 * node.Depth()
+	return x >= column && x < column+2
+}
+
+// scroll moves the viewport by delta rows, dragging the selection along when
+// it would leave the screen
+func (m *Model) scroll(delta int) {
+	rows := m.treeHeight()
+	start, _ := m.scrolledRange(rows)
+	start = min(max(start+delta, 0), max(len(m.VisibleNodes)-rows, 0))
+	
+	m.offset = start
+	m.SelectedIndex = min(max(m.SelectedIndex, start), start+rows-1)
+	m.moveSelection(0)
+}
\ No newline at end of file
@@ -0,0 +1,96 @@
+package ui
+
+import (
+	"testing"
+	
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func click(model tea.Model, x, y int) tea.Model {
+	model, _ = model.Update(tea.MouseMsg{X: x, Y: y, Action: tea.MouseActionPress, Button: tea.MouseButtonLeft})
+	return model
+}
+
+func wheel(model tea.Model, button tea.MouseButton) tea.Model {
+	model, _ = model.Update(tea.MouseMsg{Action: tea.MouseActionPress, Button: button})
+	return model
+}
+
+func TestMouse_ClickSelectsRow(t *testing.T) {
+	var model tea.Model = newWideModel(50, 10)
+	for i := 0; i < 10; i++ {
+		model = pressKey(model, "j")
+	}
+	
+	// Rows 3–10 are on screen, so the third screen row is row 5
+	model = click(model, 20, 2)
+	m := model.(Model)
+	if m.SelectedIndex != 5 {
+		t.Errorf("Expected the click to select row 5, got %d", m.SelectedIndex)
+	}
+	
+	// The scroll position line and the footer are not rows
+	for _, y := range []int{8, 9} {
+		model = click(model, 20, y)
+		if m := model.(Model); m.SelectedIndex != 5 {
+			t.Errorf("Expected a click on line %d to keep the selection, got %d", y, m.SelectedIndex)
+		}
+	}
+}
+
+func TestMouse_ClickIndicatorToggles(t *testing.T) {
+	var model tea.Model = newNestedModel()
+	
+	// Clicking the name of a only selects it
+	model = click(model, 8, 1)
+	m := model.(Model)
+	if m.selectedNode().Name != "a" || len(m.VisibleNodes) != 3 {
+		t.Fatalf("Expected a to be selected and collapsed, got %s with %d visible nodes", m.selectedNode().Name, len(m.VisibleNodes))
+	}
+	
+	// a is at depth 1, so its indicator takes columns 2 and 3
+	model = click(model, 3, 1)
+	if m := model.(Model); len(m.VisibleNodes) != 4 {
+		t.Errorf("Expected a click on the indicator to expand a, got %d visible nodes", len(m.VisibleNodes))
+	}
+	model = click(model, 2, 1)
+	if m := model.(Model); len(m.VisibleNodes) != 3 {
+		t.Errorf("Expected a second click to collapse a, got %d visible nodes", len(m.VisibleNodes))
+	}
+}
+
+func TestMouse_ClickIgnoresDetailPane(t *testing.T) {
+	model := newWideModel(5, 20)
+	model.ShowDetails = true
+	
+	// An 80 column terminal leaves 54 columns for the tree
+	updated := click(*model, 60, 2)
+	if m := updated.(Model); m.SelectedIndex != 0 {
+		t.Errorf("Expected a click in the pane to keep the selection, got %d", m.SelectedIndex)
+	}
+}
+
+func TestMouse_WheelScrolls(t *testing.T) {
+	var model tea.Model = newWideModel(50, 10)
+	
+	model = wheel(model, tea.MouseButtonWheelDown)
+	m := model.(Model)
+	if start, _ := m.visibleRange(); start != 3 || m.SelectedIndex != 3 {
+		t.Errorf("Expected the view to scroll to row 3 and drag the selection, got %d with row %d selected", start, m.SelectedIndex)
+	}
+	
+	// Scrolling up from the end drags the selection to the last row on screen
+	model = pressKey(model, "end")
+	model = wheel(model, tea.MouseButtonWheelUp)
+	m = model.(Model)
+	if start, _ := m.visibleRange(); start != 40 || m.SelectedIndex != 47 {
+		t.Errorf("Expected the view to scroll up to row 40, got %d with row %d selected", start, m.SelectedIndex)
+	}
+	
+	for i := 0; i < 20; i++ {
+		model = wheel(model, tea.MouseButtonWheelUp)
+	}
+	if start, _ := model.(Model).visibleRange(); start != 0 {
+		t.Errorf("Expected the view to stop at the top, got %d", start)
+	}
+}
\ No newline at end of file
@@ -0,0 +1,116 @@
+package ui
+
+import (
+	"github.com/user/loctree/internal/tree"
+)
+
+// toggleSelected expands or collapses the selected directory
+func (m *Model) toggleSelected() {
+	node := m.selectedNode()
+	if node == nil || !node.HasVisibleChildren(m.ShowFiles) {
+		return
+	}
+	
+	node.ToggleExpanded()
+	m.updateVisibleNodes()
+	// Adjust selected index if needed
+	if m.SelectedIndex >= len(m.VisibleNodes) {
+		m.SelectedIndex = len(m.VisibleNodes) - 1
+	}
+}
+
+// expandSubtree expands the selected directory and everything below it
+func (m *Model) expandSubtree() {
+	node := m.selectedNode()
+	if node == nil || node.IsFile {
+		return
+	}
+	
+	tree.ExpandAll(node)
+	m.updateVisibleNodes()
+	m.selectNode(node)
+}
+
+// collapseAll collapses every directory below the root, selecting the
+// top-level directory that held the selection
+func (m *Model) collapseAll() {
+	selected := m.selectedNode()
+	for _, child := range m.Root.Children {
+		tree.CollapseAll(child)
+	}
+	m.Root.IsExpanded = true
+	m.updateVisibleNodes()
+	m.selectVisible(selected)
+}
+
+// collapseParent collapses the directory holding the selected node and
+// selects it
+func (m *Model) collapseParent() {
+	node := m.selectedNode()
+	if node == nil || node.Parent == nil {
+		return
+	}
+	
+	node.Parent.IsExpanded = false
+	m.updateVisibleNodes()
+	m.selectNode(node.Parent)
+}
+
+// expandToDepth expands the whole tree down to depth levels below the root,
+// keeping the selection on the node or its closest visible ancestor
+func (m *Model) expandToDepth(depth int) {
+	selected := m.selectedNode()
+	tree.ExpandToDepth(m.Root, depth)
+	m.updateVisibleNodes()
+	m.selectVisible(selected)
+}
+
+// collapseOrParent collapses the selected directory when it is expanded and
+// otherwise moves to its parent
+func (m *Model) collapseOrParent() {
+	node := m.selectedNode()
+	if node == nil {
+		return
+	}
+	
+	if node.IsExpanded && node.HasVisibleChildren(m.ShowFiles) {
+		node.IsExpanded = false
+		m.updateVisibleNodes()
+		m.selectNode(node)
+		return
+	}
+	if node.Parent != nil {
+		m.selectNode(node.Parent)
+	}
+}
+
+// expandOrChild expands the selected directory when it is collapsed and
+// otherwise moves to its first child
+func (m *Model) expandOrChild() {
+	node := m.selectedNode()
+	if node == nil || !node.HasVisibleChildren(m.ShowFiles) {
+		return
+	}
+	
+	if !node.IsExpanded {
+		node.IsExpanded = true
+		m.updateVisibleNodes()
+		m.selectNode(node)
+		return
+	}
+	m.moveSelection(1)
+}
+
+// selectVisible selects node, or its closest ancestor when it is hidden
+// inside a collapsed directory
+func (m *Model) selectVisible(node *tree.DirectoryNode) {
+	for ; node != nil; node = node.Parent {
+		for i, visible := range m.VisibleNodes {
+			if visible == node {
+				m.SelectedIndex = i
+				return
+			}
+		}
+	}
+	m.SelectedIndex = 0
+}
\ No newline at end of file
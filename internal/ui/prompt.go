@@ -0,0 +1,156 @@
+package ui
+
+import (
+	"strings"
+	
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/user/loctree/internal/tree"
+)
+
+// prompt identifies the text prompt that takes key presses
+type prompt int
+
+const (
+	noPrompt     prompt = iota
+	searchPrompt        // Jumps to the best matching node while typing
+	filterPrompt        // Prunes the tree to matching nodes when confirmed
+)
+
+// openPrompt opens a prompt with the given initial input
+func (m *Model) openPrompt(p prompt, input string) {
+	m.prompt = p
+	m.input = input
+	m.inputErr = ""
+	if p == searchPrompt {
+		m.matches = nil
+		m.searchStart = m.selectedNode()
+	}
+}
+
+// updatePrompt handles a key press while a prompt is open
+func (m *Model) updatePrompt(msg tea.KeyMsg) tea.Cmd {
+	switch msg.Type {
+	case tea.KeyCtrlC:
+		m.quitting = true
+		return tea.Quit
+		
+	case tea.KeyEsc:
+		m.cancelPrompt()
+		
+	case tea.KeyEnter:
+		m.confirmPrompt()
+		
+	case tea.KeyBackspace:
+		if input := []rune(m.input); len(input) > 0 {
+			m.input = string(input[:len(input)-1])
+			m.inputChanged()
+		}
+		
+	case tea.KeyRunes, tea.KeySpace:
+		m.input += string(msg.Runes)
+		m.inputChanged()
+	}
+	return nil
+}
+
+// inputChanged searches again as the search query is edited, and checks
+// the filter expression as it is typed
+func (m *Model) inputChanged() {
+	switch m.prompt {
+	case searchPrompt:
+		m.search()
+	case filterPrompt:
+		m.inputErr = ""
+		if _, err := tree.ParseQuery(m.input); err != nil {
+			m.inputErr = err.Error()
+		}
+	}
+}
+
+// cancelPrompt closes the prompt. A cancelled search returns to the node
+// selected before it started.
+func (m *Model) cancelPrompt() {
+	if m.prompt == searchPrompt {
+		m.matches = nil
+		if m.searchStart != nil {
+			m.jumpTo(m.searchStart)
+		}
+	}
+	m.prompt = noPrompt
+}
+
+// confirmPrompt closes the prompt, applying a filter expression. An invalid
+// expression keeps the prompt open.
+func (m *Model) confirmPrompt() {
+	if m.prompt == filterPrompt {
+		if err := m.SetFilter(m.input); err != nil {
+			m.inputErr = err.Error()
+			return
+		}
+	}
+	m.prompt = noPrompt
+}
+
+// search finds every node matching the query, including nodes inside
+// collapsed directories, and selects the best match
+func (m *Model) search() {
+	m.matches = tree.Search(m.Root, m.input, m.ShowFiles)
+	m.matchIndex = 0
+	if len(m.matches) > 0 {
+		m.jumpTo(m.matches[0])
+	}
+}
+
+// nextMatch selects the match delta places after the current one, wrapping
+// around at either end
+func (m *Model) nextMatch(delta int) {
+	if len(m.matches) == 0 {
+		return
+	}
+	m.matchIndex = ((m.matchIndex+delta)%len(m.matches) + len(m.matches)) % len(m.matches)
+	m.jumpTo(m.matches[m.matchIndex])
+}
+
+// SetFilter shows only the nodes matching a filter expression (see
+// tree.ParseQuery) and their ancestors, with counts recomputed from the
+// matching files. An empty expression shows the whole tree again, expanded
+// as it was before filtering. The selected node stays selected, or its
+// closest visible ancestor when it is filtered out.
+func (m *Model) SetFilter(expr string) error {
+	q, err := tree.ParseQuery(expr)
+	if err != nil {
+		return err
+	}
+	
+	selectedPath := "."
+	if node := m.selectedNode(); node != nil {
+		selectedPath = node.RelativePath()
+	}
+	
+	whole := m.Root
+	if m.unfiltered != nil {
+		whole = m.unfiltered
+	}
+	if q.IsEmpty() {
+		m.Root = whole
+		m.unfiltered = nil
+		m.filter = ""
+	} else {
+		m.Root = tree.Prune(whole, q, m.Metric)
+		m.unfiltered = whole
+		m.filter = strings.Join(strings.Fields(expr), " ")
+	}
+	
+	// Matches of an earlier search may no longer be in the tree
+	m.matches = nil
+	m.sortTree()
+	m.updateVisibleNodes()
+	m.selectPath(selectedPath)
+	return nil
+}
+
+// selectPath selects the node at a relative path, or its closest visible
+// ancestor
+func (m *Model) selectPath(relPath string) {
+	m.selectVisible(tree.FindPath(m.Root, relPath))
+}
\ No newline at end of file
@@ -0,0 +1,130 @@
+package ui
+
+import (
+	"strings"
+	"testing"
+	
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/user/loctree/internal/scanner"
+	"github.com/user/loctree/internal/tree"
+)
+
+// newFilterTree builds root/{src/{main.go,main_test.go},docs/guide.md} with
+// root and src expanded and docs collapsed
+func newFilterTree() *tree.DirectoryNode {
+	root := tree.NewDirectoryNode("root", "/root")
+	src := tree.NewDirectoryNode("src", "/root/src")
+	docs := tree.NewDirectoryNode("docs", "/root/docs")
+	root.AddChild(src)
+	root.AddChild(docs)
+	
+	for _, f := range []struct {
+		dir  *tree.DirectoryNode
+		name string
+		lang string
+		loc  int
+	}{
+		{src, "main.go", "Go", 300},
+		{src, "main_test.go", "Go", 200},
+		{docs, "guide.md", "Markdown", 100},
+	} {
+		f.dir.AddCountedFile(f.name, scanner.FileStats{Language: f.lang, LineCounts: scanner.LineCounts{Lines: f.loc}})
+	}
+	
+	root.CalculateLOC()
+	root.IsExpanded = true
+	src.IsExpanded = true
+	return root
+}
+
+func TestUpdate_Filter(t *testing.T) {
+	root := newFilterTree()
+	var model tea.Model = NewModelWithOptions(root, Options{ShowFiles: true})
+	
+	model = typeKeys(model, "f*_test.go")
+	model, _ = model.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	
+	m := model.(Model)
+	if m.prompt != noPrompt || m.filter != "*_test.go" {
+		t.Fatalf("Expected the filter to be applied, got prompt %d and filter %q", m.prompt, m.filter)
+	}
+	if m.Root.LOC != 200 {
+		t.Errorf("Expected the root to count only the matching 200 lines, got %d", m.Root.LOC)
+	}
+	var names []string
+	for _, node := range m.VisibleNodes {
+		names = append(names, node.Name)
+	}
+	if strings.Join(names, " ") != "root src main_test.go" {
+		t.Errorf("Expected root src main_test.go, got %v", names)
+	}
+	if view := m.View(); !strings.Contains(view, "filter: *_test.go") {
+		t.Errorf("Expected the active filter in the view, got:\n%s", view)
+	}
+	
+	// Clearing the filter restores the whole tree as it was expanded
+	model = typeKeys(model, "j")
+	model = typeKeys(model, "f")
+	for range "*_test.go" {
+		model, _ = model.Update(tea.KeyMsg{Type: tea.KeyBackspace})
+	}
+	model, _ = model.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	
+	m = model.(Model)
+	if m.Root != root || m.filter != "" {
+		t.Fatal("Expected the whole tree to be shown again")
+	}
+	if len(m.VisibleNodes) != 5 || root.Children[1].IsExpanded {
+		t.Errorf("Expected the previous expansion state, got %d visible nodes", len(m.VisibleNodes))
+	}
+	if selected := m.selectedNode(); selected.Name != "src" {
+		t.Errorf("Expected src to stay selected, got %s", selected.Name)
+	}
+}
+
+func TestUpdate_FilterInvalid(t *testing.T) {
+	var model tea.Model = NewModel(newFilterTree())
+	
+	model = typeKeys(model, "f>abc")
+	model, _ = model.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	
+	m := model.(Model)
+	if m.prompt != filterPrompt {
+		t.Fatal("Expected an invalid filter to keep the prompt open")
+	}
+	if m.filter != "" || !strings.Contains(m.View(), "invalid line count") {
+		t.Errorf("Expected the error to be shown, got:\n%s", m.View())
+	}
+	
+	model, _ = model.Update(tea.KeyMsg{Type: tea.KeyEsc})
+	if m := model.(Model); m.prompt != noPrompt || m.filter != "" {
+		t.Error("Expected escape to close the prompt without filtering")
+	}
+}
+
+func TestSetFilter_SelectsClosestAncestor(t *testing.T) {
+	model := NewModelWithOptions(newFilterTree(), Options{ShowFiles: true})
+	model.selectPath("src/main.go")
+	
+	if err := model.SetFilter("lang:markdown"); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if selected := model.selectedNode(); selected != model.Root {
+		t.Errorf("Expected the root to be selected, got %s", selected.Name)
+	}
+	if model.Root.LOC != 100 {
+		t.Errorf("Expected 100 Markdown lines, got %d", model.Root.LOC)
+	}
+	
+	// The minimum line count follows the metric
+	if err := model.SetFilter(">150"); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(model.VisibleNodes) != 4 {
+		t.Errorf("Expected root, src, main.go and main_test.go, got %d nodes", len(model.VisibleNodes))
+	}
+	model.SetMetric(tree.MetricCode)
+	if len(model.VisibleNodes) != 1 {
+		t.Errorf("Expected only the root without code lines, got %d nodes", len(model.VisibleNodes))
+	}
+}
\ No newline at end of file
@@ -5,69 +5,280 @@ import (
 	"strings"
 	
 	"github.com/charmbracelet/lipgloss"
+	"github.com/user/loctree/internal/format"
+	"github.com/user/loctree/internal/history"
 	"github.com/user/loctree/internal/tree"
 )
 
+// Styles of the viewer, in the colours of the theme set by SetTheme
 var (
-	normalStyle = lipgloss.NewStyle().
-		Foreground(lipgloss.Color("252"))
-	
-	selectedStyle = lipgloss.NewStyle().
-		Foreground(lipgloss.Color("86")).
-		Bold(true)
-	
-	indicatorStyle = lipgloss.NewStyle().
-		Foreground(lipgloss.Color("241"))
-	
-	locStyle = lipgloss.NewStyle().
-		Foreground(lipgloss.Color("214"))
+	normalStyle    lipgloss.Style
+	selectedStyle  lipgloss.Style
+	indicatorStyle lipgloss.Style
+	locStyle       lipgloss.Style
+	summaryStyle   lipgloss.Style
+	fileStyle      lipgloss.Style
+	addedStyle     lipgloss.Style
+	removedStyle   lipgloss.Style
+	statusBarStyle lipgloss.Style
+	helpTitleStyle lipgloss.Style
+	paneStyle      lipgloss.Style
+)
+
+// maxSummaryLanguages limits how many languages the summary line lists
+const maxSummaryLanguages = 6
+
+// Bar column widths: a fraction of the terminal width within these limits,
+// or the default when the width is unknown
+const (
+	minBarWidth     = 6
+	maxBarWidth     = 30
+	defaultBarWidth = 20
 )
 
+// Renderer formats tree rows using the current display settings
+type Renderer struct {
+	Metric    tree.Metric    // Line count shown for each node
+	ShowFiles bool           // Files are listed as leaves under their directory
+	Width     int            // Rows are cut to this width; zero leaves them whole
+	Columns   format.Columns // Optional columns shown to the right of each row
+}
+
 // RenderNode renders a single node with proper formatting
 func RenderNode(node *tree.DirectoryNode, depth int, selected bool) string {
-	indent := strings.Repeat("  ", depth)
-	
-	// Determine indicator
-	indicator := ""
-	if len(node.Children) > 0 {
-		if node.IsExpanded {
-			indicator = "▼ "
-		} else {
-			indicator = "▶ "
-		}
+	return Renderer{}.RenderNode(node, depth, selected)
+}
+
+// RenderTree renders the entire visible tree
+func RenderTree(visibleNodes []*tree.DirectoryNode, selectedIndex int) string {
+	return Renderer{}.RenderTree(visibleNodes, selectedIndex)
+}
+
+// RenderNode renders a single node with proper formatting
+func (r Renderer) RenderNode(node *tree.DirectoryNode, depth int, selected bool) string {
+	if node.Change != nil {
+		return r.renderChange(node, depth, selected)
 	}
 	
 	// Format the line
-	line := fmt.Sprintf("%s%s%d %s", indent, indicator, node.LOC, node.Name)
+	line := format.TreeRow(node, depth, r.Metric, r.ShowFiles)
+	return rowStyle(node, selected).Render(line)
+}
+
+// renderChange renders a node of a diff tree as "+1,203 / -88 name",
+// with the added and removed counts coloured
+func (r Renderer) renderChange(node *tree.DirectoryNode, depth int, selected bool) string {
+	style := rowStyle(node, selected)
+	change := node.Change
 	
-	// Apply style based on selection
+	name := node.Name
+	if change.Status == tree.Added || change.Status == tree.Removed {
+		name += fmt.Sprintf(" (%s)", change.Status)
+	}
+	
+	return style.Render(format.TreePrefix(node, depth, r.ShowFiles)) +
+		addedStyle.Render("+"+format.Number(change.Added)) +
+		style.Render(" / ") +
+		removedStyle.Render("-"+format.Number(change.Removed)) +
+		style.Render(" "+name)
+}
+
+// rowStyle returns the style of a tree row based on selection
+func rowStyle(node *tree.DirectoryNode, selected bool) lipgloss.Style {
 	if selected {
-		return selectedStyle.Render(line)
+		return selectedStyle
+	}
+	if node.IsFile {
+		return fileStyle
 	}
-	return normalStyle.Render(line)
+	return normalStyle
 }
 
 // RenderTree renders the entire visible tree
-func RenderTree(visibleNodes []*tree.DirectoryNode, selectedIndex int) string {
+func (r Renderer) RenderTree(visibleNodes []*tree.DirectoryNode, selectedIndex int) string {
 	var lines []string
 	
 	for i, node := range visibleNodes {
 		selected := i == selectedIndex
-		depth := getNodeDepth(node)
-		line := RenderNode(node, depth, selected)
+		depth := node.Depth()
+		line := r.RenderNode(node, depth, selected)
 		lines = append(lines, line)
 	}
+	if r.Columns != 0 {
+		r.addColumns(visibleNodes, lines)
+	}
+	
+	if r.Width > 0 {
+		for i, line := range lines {
+			lines[i] = lipgloss.NewStyle().MaxWidth(r.Width).Render(line)
+		}
+	}
 	
 	return strings.Join(lines, "\n")
 }
 
-// getNodeDepth calculates the depth of a node in the tree
-func getNodeDepth(node *tree.DirectoryNode) int {
-	depth := 0
-	current := node
-	for current.Parent != nil {
-		depth++
-		current = current.Parent
+// addColumns pads the rendered rows to a common width and appends the
+// selected columns. With a known terminal width the tree part takes the
+// space left by the columns, cutting long names, so the columns line up at
+// the right edge.
+func (r Renderer) addColumns(nodes []*tree.DirectoryNode, lines []string) {
+	barWidth := defaultBarWidth
+	if r.Width > 0 {
+		barWidth = min(max(r.Width/6, minBarWidth), maxBarWidth)
 	}
-	return depth
+	
+	cells := make([]string, len(nodes))
+	for i, node := range nodes {
+		var parts []string
+		for _, col := range r.Columns.List() {
+			cell := format.Cell(col, node, r.Metric, barWidth)
+			if col == format.ColumnBar {
+				parts = append(parts, locStyle.Render(cell))
+			} else {
+				parts = append(parts, summaryStyle.Render(cell))
+			}
+		}
+		cells[i] = strings.Join(parts, " ")
+	}
+	
+	treeWidth := 0
+	if r.Width > 0 && len(cells) > 0 {
+		treeWidth = max(r.Width-lipgloss.Width(cells[0])-2, 1)
+	} else {
+		for _, line := range lines {
+			treeWidth = max(treeWidth, lipgloss.Width(line))
+		}
+	}
+	
+	for i, line := range lines {
+		line = lipgloss.NewStyle().MaxWidth(treeWidth).Render(line)
+		lines[i] = line + strings.Repeat(" ", treeWidth-lipgloss.Width(line)+2) + cells[i]
+	}
+}
+
+// scrollPosition describes which rows of the tree are on screen, e.g.
+// "↑↓ 31–60 of 230", or returns nothing when the whole tree fits
+func scrollPosition(start, end, total int) string {
+	if start <= 0 && end >= total {
+		return ""
+	}
+	
+	arrows := ""
+	if start > 0 {
+		arrows += "↑"
+	}
+	if end < total {
+		arrows += "↓"
+	}
+	return fmt.Sprintf("%s %d–%d of %d", arrows, start+1, end, total)
+}
+
+// RenderSearch renders the search prompt with the position of the selected
+// match, e.g. "/int/sc  1 of 3"
+func RenderSearch(query string, index, total int) string {
+	prompt := selectedStyle.Render("/" + query)
+	switch {
+	case query == "":
+		return prompt
+	case total == 0:
+		return prompt + summaryStyle.Render("  no matches")
+	}
+	return prompt + summaryStyle.Render(fmt.Sprintf("  %d of %d", index+1, total))
+}
+
+// RenderFilter renders the filter prompt while it is edited, with any error
+// in the expression
+func RenderFilter(expr string, errText string) string {
+	prompt := selectedStyle.Render("filter: " + expr)
+	if errText != "" {
+		prompt += removedStyle.Render("  " + errText)
+	}
+	return prompt
+}
+
+// RenderStatusBar renders the bar at the bottom of the screen: the left
+// segments, cut to make room when the width is too small, and the right
+// segments against the right edge
+func RenderStatusBar(left, right []string, width int) string {
+	leftText := " " + strings.Join(left, " │ ")
+	rightText := strings.Join(right, " │ ") + " "
+	if width <= 0 {
+		return statusBarStyle.Render(leftText + "   " + rightText)
+	}
+	
+	room := max(width-lipgloss.Width(rightText)-1, 0)
+	leftText = lipgloss.NewStyle().MaxWidth(room).Render(leftText)
+	gap := max(width-lipgloss.Width(leftText)-lipgloss.Width(rightText), 1)
+	bar := leftText + strings.Repeat(" ", gap) + rightText
+	return statusBarStyle.Render(lipgloss.NewStyle().MaxWidth(width).Render(bar))
+}
+
+// RenderStatus renders the progress or failure of a rescan
+func RenderStatus(status string) string {
+	return summaryStyle.Render(status)
+}
+
+// RenderSummary renders the language breakdown of a node
+func RenderSummary(node *tree.DirectoryNode) string {
+	if node == nil || len(node.Languages) == 0 {
+		return ""
+	}
+	
+	return summaryStyle.Render(format.Languages(node.LanguageBreakdown(), maxSummaryLanguages))
+}
+
+// RenderCounts renders the line, code, comment and blank totals of a node,
+// highlighting the metric that drives the display
+func RenderCounts(node *tree.DirectoryNode, active tree.Metric) string {
+	if node == nil {
+		return ""
+	}
+	
+	metrics := []tree.Metric{tree.MetricLines, tree.MetricCode, tree.MetricComments, tree.MetricBlanks}
+	parts := make([]string, 0, len(metrics))
+	for _, metric := range metrics {
+		part := fmt.Sprintf("%s %s", format.Number(node.Count(metric)), metric)
+		if metric == active {
+			parts = append(parts, locStyle.Render(part))
+		} else {
+			parts = append(parts, summaryStyle.Render(part))
+		}
+	}
+	
+	return strings.Join(parts, summaryStyle.Render(" · "))
+}
+
+// RenderChange renders how a node of a diff tree changed, e.g.
+// "4,000 → 5,115 lines (+1,203 / -88, changed)"
+func RenderChange(node *tree.DirectoryNode) string {
+	if node == nil || node.Change == nil {
+		return ""
+	}
+	
+	change := node.Change
+	return summaryStyle.Render(fmt.Sprintf("%s → %s lines (", format.Number(change.OldLOC), format.Number(node.LOC))) +
+		addedStyle.Render("+"+format.Number(change.Added)) +
+		summaryStyle.Render(" / ") +
+		removedStyle.Render("-"+format.Number(change.Removed)) +
+		summaryStyle.Render(fmt.Sprintf(", %s)", change.Status))
+}
+
+// RenderHistory renders a sparkline of a directory's count over the sampled
+// history, e.g. "▁▂▄▇█ 1,200 → 2,345 lines (weekly since 2026-04-18)"
+func RenderHistory(node *tree.DirectoryNode, h *history.History) string {
+	if node == nil {
+		return ""
+	}
+	series := h.Series(node.RelativePath())
+	if len(series) == 0 {
+		return ""
+	}
+	
+	summary := fmt.Sprintf(" %s → %s %s (%s since %s)",
+		format.Number(series[0]),
+		format.Number(series[len(series)-1]),
+		h.Metric,
+		h.Step,
+		h.Samples[0].Time.Format("2006-01-02"))
+	return locStyle.Render(format.Sparkline(series)) + summaryStyle.Render(summary)
 }
\ No newline at end of file
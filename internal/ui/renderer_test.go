@@ -3,7 +3,11 @@ package ui
 import (
 	"strings"
 	"testing"
+	"time"
 	
+	"github.com/charmbracelet/lipgloss"
+	"github.com/user/loctree/internal/format"
+	"github.com/user/loctree/internal/history"
 	"github.com/user/loctree/internal/tree"
 )
 
@@ -90,4 +94,138 @@ func TestRenderNode_NoChildren(t *testing.T) {
 	if strings.Contains(result, "▶") || strings.Contains(result, "▼") {
 		t.Error("Leaf node should not have expand/collapse indicator")
 	}
+}
+
+func TestRenderSummary(t *testing.T) {
+	node := tree.NewDirectoryNode("root", "/root")
+	node.FileLanguages["Go"] = 12340
+	node.FileLanguages["YAML"] = 800
+	node.CalculateLOC()
+	
+	result := RenderSummary(node)
+	
+	if !strings.Contains(result, "Go 12,340 · YAML 800") {
+		t.Errorf("Expected language breakdown in summary, got: %s", result)
+	}
+}
+
+func TestRenderSummary_NoLanguages(t *testing.T) {
+	node := tree.NewDirectoryNode("empty", "/empty")
+	
+	if result := RenderSummary(node); result != "" {
+		t.Errorf("Expected empty summary, got: %s", result)
+	}
+}
+
+func TestRenderer_UsesMetric(t *testing.T) {
+	node := tree.NewDirectoryNode("test", "/test")
+	node.LOC = 100
+	node.Code = 42
+	
+	result := Renderer{Metric: tree.MetricCode}.RenderNode(node, 0, false)
+	
+	if !strings.Contains(result, "42 test") {
+		t.Errorf("Expected code count in output, got: %s", result)
+	}
+}
+
+func TestRenderCounts(t *testing.T) {
+	node := tree.NewDirectoryNode("test", "/test")
+	node.LOC = 1500
+	node.Code = 1200
+	node.Comments = 200
+	node.Blanks = 100
+	
+	result := RenderCounts(node, tree.MetricCode)
+	
+	for _, expected := range []string{"1,500 lines", "1,200 code", "200 comments", "100 blanks"} {
+		if !strings.Contains(result, expected) {
+			t.Errorf("Expected '%s' in counts, got: %s", expected, result)
+		}
+	}
+}
+
+func TestRenderNode_File(t *testing.T) {
+	file := tree.NewFileNode("main.go", "/root/main.go")
+	file.LOC = 42
+	file.Language = "Go"
+	file.Size = 2048
+	
+	result := RenderNode(file, 1, false)
+	
+	if !strings.Contains(result, "42 main.go (Go, 2.0 KB)") {
+		t.Errorf("Expected LOC, name, language and size in output, got: %s", result)
+	}
+	if strings.Contains(result, "▶") || strings.Contains(result, "▼") {
+		t.Error("File node should not have expand/collapse indicator")
+	}
+}
+
+func TestRenderNode_DirectoryWithOnlyFiles(t *testing.T) {
+	dir := tree.NewDirectoryNode("dir", "/dir")
+	dir.AddFile(tree.NewFileNode("main.go", "/dir/main.go"))
+	
+	if strings.Contains(RenderNode(dir, 0, false), "▶") {
+		t.Error("Expected no indicator when files are hidden")
+	}
+	if !strings.Contains(Renderer{ShowFiles: true}.RenderNode(dir, 0, false), "▶") {
+		t.Error("Expected collapsed indicator when files are shown")
+	}
+}
+
+func TestRenderHistory(t *testing.T) {
+	root := tree.NewDirectoryNode("root", "/root")
+	h := &history.History{
+		Step:    history.Weekly,
+		Samples: []history.Sample{{Time: time.Date(2026, 4, 18, 0, 0, 0, 0, time.UTC)}, {}},
+		Counts:  map[string][]int{".": {1200, 2345}},
+	}
+	
+	rendered := RenderHistory(root, h)
+	if !strings.Contains(rendered, "▁█") || !strings.Contains(rendered, "1,200 → 2,345 lines (weekly since 2026-04-18)") {
+		t.Errorf("Unexpected history line: %s", rendered)
+	}
+	if RenderHistory(root, nil) != "" {
+		t.Error("Expected no history line without a history")
+	}
+}
+
+func TestRenderTree_Columns(t *testing.T) {
+	root := tree.NewDirectoryNode("root", "/root")
+	root.IsExpanded = true
+	short := tree.NewDirectoryNode("a", "/root/a")
+	short.FileLOC = 75
+	long := tree.NewDirectoryNode("a-much-longer-name", "/root/a-much-longer-name")
+	long.FileLOC = 25
+	root.AddChild(short)
+	root.AddChild(long)
+	root.CalculateLOC()
+	nodes := tree.GetVisibleNodes(root)
+	
+	renderer := Renderer{Columns: format.ColumnParent | format.ColumnBar}
+	lines := strings.Split(renderer.RenderTree(nodes, 0), "\n")
+	
+	// Without a terminal width the columns start after the longest row
+	columnOf := func(line, cell string) int {
+		return lipgloss.Width(line[:strings.Index(line, cell)])
+	}
+	column := columnOf(lines[0], "100.0%")
+	if columnOf(lines[1], " 75.0%") != column || columnOf(lines[2], " 25.0%") != column {
+		t.Errorf("Expected aligned share columns, got:\n%s", strings.Join(lines, "\n"))
+	}
+	if !strings.HasSuffix(lines[1], "███████████████     ") {
+		t.Errorf("Expected a 15 of 20 character bar, got %q", lines[1])
+	}
+	
+	// With a terminal width the rows fill it and long names are cut
+	renderer.Width = 30
+	lines = strings.Split(renderer.RenderTree(nodes, 0), "\n")
+	for _, line := range lines {
+		if width := lipgloss.Width(line); width != 30 {
+			t.Errorf("Expected rows 30 characters wide, got %d: %q", width, line)
+		}
+	}
+	if strings.Contains(lines[2], "a-much-longer-name") {
+		t.Errorf("Expected the long name to be cut, got %q", lines[2])
+	}
 }
\ No newline at end of file
@@ -0,0 +1,138 @@
+package ui
+
+import (
+	"time"
+	
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/user/loctree/internal/tree"
+	"github.com/user/loctree/internal/watch"
+)
+
+// rescannedMsg carries a fresh scan of the whole tree
+type rescannedMsg struct {
+	root     *tree.DirectoryNode
+	err      error
+	duration time.Duration
+}
+
+// watchStartedMsg carries the watcher of the scanned directory
+type watchStartedMsg struct {
+	watcher *watch.Watcher
+	err     error
+}
+
+// changesMsg carries paths the watcher saw change
+type changesMsg struct {
+	paths []string
+}
+
+// patchedMsg carries the rescanned state of changed paths
+type patchedMsg struct {
+	patches []tree.Patch
+	err     error
+}
+
+// rescan starts scanning the directory again in the background, showing
+// the current tree until the scan is done
+func (m *Model) rescan() tea.Cmd {
+	if m.scanPath == "" || m.rescanning {
+		return nil
+	}
+	
+	m.rescanning = true
+	m.status = "Rescanning…"
+	path, opts := m.scanPath, m.build
+	return func() tea.Msg {
+		start := time.Now()
+		root, err := tree.BuildTreeWithOptions(path, opts)
+		return rescannedMsg{root: root, err: err, duration: time.Since(start)}
+	}
+}
+
+// startWatch starts watching the scanned directory for changes
+func (m Model) startWatch() tea.Cmd {
+	path, opts := m.scanPath, m.build
+	return func() tea.Msg {
+		watcher, err := watch.New(path, opts.Filter)
+		return watchStartedMsg{watcher: watcher, err: err}
+	}
+}
+
+// waitForChanges waits for the watcher's next batch of changed paths
+func waitForChanges(watcher *watch.Watcher) tea.Cmd {
+	return func() tea.Msg {
+		paths, ok := <-watcher.Changes()
+		if !ok {
+			return nil
+		}
+		return changesMsg{paths: paths}
+	}
+}
+
+// rescanPaths counts the changed paths again in the background
+func (m Model) rescanPaths(paths []string) tea.Cmd {
+	path, opts := m.scanPath, m.build
+	return func() tea.Msg {
+		patches, err := tree.RescanPaths(path, opts, paths)
+		return patchedMsg{patches: patches, err: err}
+	}
+}
+
+// updateScan handles the results of rescans and the watcher
+func (m *Model) updateScan(msg tea.Msg) tea.Cmd {
+	switch msg := msg.(type) {
+	case rescannedMsg:
+		m.rescanning = false
+		m.status = ""
+		if msg.err != nil {
+			m.status = "Rescan failed: " + msg.err.Error()
+			return nil
+		}
+		m.scanDuration = msg.duration
+		m.setScanned(msg.root)
+		
+	case watchStartedMsg:
+		if msg.err != nil {
+			m.status = "Watching failed: " + msg.err.Error()
+			return nil
+		}
+		m.watcher = msg.watcher
+		return waitForChanges(m.watcher)
+		
+	case changesMsg:
+		return m.rescanPaths(msg.paths)
+		
+	case patchedMsg:
+		if msg.err != nil {
+			m.status = "Update failed: " + msg.err.Error()
+		} else {
+			m.setScanned(tree.ApplyPatches(m.scanned, msg.patches))
+		}
+		return waitForChanges(m.watcher)
+	}
+	return nil
+}
+
+// setScanned shows a new or updated scan of the directory, compared with
+// the base in diff mode, keeping the expansion state, filter and selection
+func (m *Model) setScanned(scanned *tree.DirectoryNode) {
+	whole := m.Root
+	if m.unfiltered != nil {
+		whole = m.unfiltered
+	}
+	
+	root := scanned
+	if m.base != nil {
+		root = tree.Diff(m.base, scanned)
+	}
+	tree.CopyExpansion(whole, root)
+	
+	m.scanned = scanned
+	m.Root = root
+	m.unfiltered = nil
+	// The filter was valid when it was set
+	_ = m.SetFilter(m.filter)
+	if m.searchStart != nil {
+		m.searchStart = tree.FindPath(m.Root, m.searchStart.RelativePath())
+	}
+}
\ No newline at end of file
@@ -0,0 +1,108 @@
+package ui
+
+import (
+	"strings"
+	"testing"
+	
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/user/loctree/internal/testutil"
+	"github.com/user/loctree/internal/tree"
+)
+
+// newScannedModel scans a temporary directory holding the given files into
+// a model that can rescan it
+func newScannedModel(t *testing.T, files map[string]string, opts Options) (*Model, string) {
+	t.Helper()
+	dir := t.TempDir()
+	testutil.WriteFiles(t, dir, files)
+	
+	root, err := tree.BuildTree(dir)
+	if err != nil {
+		t.Fatalf("Error building tree: %v", err)
+	}
+	m := NewModelWithOptions(root, opts)
+	m.scanPath = dir
+	return m, dir
+}
+
+// runCmd runs a command and passes its message to the model
+func runCmd(t *testing.T, model tea.Model, cmd tea.Cmd) (tea.Model, tea.Cmd) {
+	t.Helper()
+	if cmd == nil {
+		t.Fatal("Expected a command, got nil")
+	}
+	return model.Update(cmd())
+}
+
+func TestUpdate_RescanKeepsExpansionAndSelection(t *testing.T) {
+	m, dir := newScannedModel(t, map[string]string{
+		"big/a.go":       "package a\n\nfunc A() {}\n",
+		"small/lib/b.go": "package b\n",
+	}, Options{Depth: 1})
+	m.SelectedIndex = 2 // small
+	m.selectedNode().IsExpanded = true
+	m.updateVisibleNodes()
+	
+	testutil.WriteFiles(t, dir, map[string]string{"small/lib/c.go": "package b\n\n\n\n\n"})
+	
+	var model tea.Model = *m
+	model, cmd := model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("r")})
+	if view := model.View(); !strings.Contains(view, "Rescanning…") {
+		t.Errorf("Expected the rescan in the footer, got:\n%s", view)
+	}
+	model, _ = runCmd(t, model, cmd)
+	
+	updated := model.(Model)
+	small := updated.selectedNode()
+	if small.Name != "small" || small.LOC != 6 {
+		t.Fatalf("Expected small with 6 lines to stay selected, got %s with %d", small.Name, small.LOC)
+	}
+	if !small.IsExpanded || len(updated.VisibleNodes) != 4 {
+		t.Errorf("Expected the expansion state to be kept, got %d visible nodes", len(updated.VisibleNodes))
+	}
+	if updated.Root.Children[0] != small {
+		t.Error("Expected the tree to be sorted again")
+	}
+	if updated.rescanning || updated.status != "" {
+		t.Errorf("Expected the rescan to be done, got status %q", updated.status)
+	}
+}
+
+func TestUpdate_RescanWithoutDirectory(t *testing.T) {
+	var model tea.Model = *NewModel(tree.NewDirectoryNode("root", "/root"))
+	if _, cmd := model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("r")}); cmd != nil {
+		t.Error("Expected no rescan of a tree not scanned from disk")
+	}
+}
+
+func TestUpdate_ChangesPatchDiffAndFilter(t *testing.T) {
+	base, err := tree.BuildTree(t.TempDir())
+	if err != nil {
+		t.Fatalf("Error building tree: %v", err)
+	}
+	m, dir := newScannedModel(t, map[string]string{
+		"src/main.go": "package main\n",
+		"README.md":   "# Readme\n",
+	}, Options{Base: base, Depth: 1})
+	if err := m.SetFilter("*.go"); err != nil {
+		t.Fatalf("Error filtering: %v", err)
+	}
+	
+	testutil.WriteFiles(t, dir, map[string]string{"src/util.go": "package main\n\nfunc util() {}\n"})
+	
+	var model tea.Model = *m
+	model, cmd := model.Update(changesMsg{paths: []string{"src/util.go"}})
+	model, _ = runCmd(t, model, cmd)
+	
+	updated := model.(Model)
+	src := updated.Root.Children[0]
+	if src.Change == nil || src.Change.Added != 4 {
+		t.Errorf("Expected src to be compared with the base, got %+v", src.Change)
+	}
+	if updated.filter != "*.go" || updated.Root.LOC != 4 {
+		t.Errorf("Expected the filter to stay applied, got %q with %d lines", updated.filter, updated.Root.LOC)
+	}
+	if updated.scanned.LOC != 5 {
+		t.Errorf("Expected the scanned tree to hold 5 lines, got %d", updated.scanned.LOC)
+	}
+}
\ No newline at end of file
@@ -0,0 +1,146 @@
+package ui
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	
+	"github.com/charmbracelet/lipgloss"
+)
+
+// Theme holds the colours of the viewer: ANSI 256-colour numbers such as
+// "86", or hex codes such as "#5fd7af"
+type Theme struct {
+	Text          lipgloss.Color // Directory rows
+	Selected      lipgloss.Color // The selected row, prompts and help titles
+	Muted         lipgloss.Color // Expand indicators and the detail pane border
+	Count         lipgloss.Color // Line counts and sparklines
+	Summary       lipgloss.Color // Labels and secondary text
+	File          lipgloss.Color // File rows
+	Added         lipgloss.Color // Lines added since the base
+	Removed       lipgloss.Color // Lines removed since the base, and errors
+	Bar           lipgloss.Color // Status bar text
+	BarBackground lipgloss.Color // Status bar background
+}
+
+// Themes are the built-in themes by name. The dark theme is the default.
+var Themes = map[string]Theme{
+	"dark": {
+		Text:          "252",
+		Selected:      "86",
+		Muted:         "241",
+		Count:         "214",
+		Summary:       "245",
+		File:          "247",
+		Added:         "42",
+		Removed:       "203",
+		Bar:           "252",
+		BarBackground: "236",
+	},
+	"light": {
+		Text:          "235",
+		Selected:      "30",
+		Muted:         "246",
+		Count:         "166",
+		Summary:       "242",
+		File:          "240",
+		Added:         "28",
+		Removed:       "160",
+		Bar:           "235",
+		BarBackground: "253",
+	},
+}
+
+// defaultTheme names the theme used when none is chosen
+const defaultTheme = "dark"
+
+func init() {
+	SetTheme(Themes[defaultTheme])
+}
+
+// LoadTheme returns the built-in theme called name, or the default theme
+// when name is empty, with colours replaced by name, e.g. "count" or
+// "bar-background"
+func LoadTheme(name string, colors map[string]string) (Theme, error) {
+	if name == "" {
+		name = defaultTheme
+	}
+	theme, ok := Themes[name]
+	if !ok {
+		return Theme{}, fmt.Errorf("unknown theme %q (expected %s)", name, strings.Join(themeNames(), ", "))
+	}
+	
+	fields := theme.colorFields()
+	for colorName, value := range colors {
+		field, ok := fields[colorName]
+		if !ok {
+			return Theme{}, fmt.Errorf("unknown colour %q", colorName)
+		}
+		if !validColor(value) {
+			return Theme{}, fmt.Errorf("invalid colour %q for %s (expected 0–255 or a hex code like #5fd7af)", value, colorName)
+		}
+		*field = lipgloss.Color(value)
+	}
+	return theme, nil
+}
+
+// SetTheme restyles the viewer in the colours of theme
+func SetTheme(theme Theme) {
+	normalStyle = lipgloss.NewStyle().Foreground(theme.Text)
+	selectedStyle = lipgloss.NewStyle().Foreground(theme.Selected).Bold(true)
+	indicatorStyle = lipgloss.NewStyle().Foreground(theme.Muted)
+	locStyle = lipgloss.NewStyle().Foreground(theme.Count)
+	summaryStyle = lipgloss.NewStyle().Foreground(theme.Summary)
+	fileStyle = lipgloss.NewStyle().Foreground(theme.File)
+	addedStyle = lipgloss.NewStyle().Foreground(theme.Added)
+	removedStyle = lipgloss.NewStyle().Foreground(theme.Removed)
+	statusBarStyle = lipgloss.NewStyle().Foreground(theme.Bar).Background(theme.BarBackground)
+	helpTitleStyle = selectedStyle
+	paneStyle = lipgloss.NewStyle().
+		BorderStyle(lipgloss.NormalBorder()).
+		BorderLeft(true).
+		BorderForeground(theme.Muted).
+		PaddingLeft(1)
+}
+
+// colorFields returns the colours of the theme by their names in config
+// files
+func (t *Theme) colorFields() map[string]*lipgloss.Color {
+	return map[string]*lipgloss.Color{
+		"text":           &t.Text,
+		"selected":       &t.Selected,
+		"muted":          &t.Muted,
+		"count":          &t.Count,
+		"summary":        &t.Summary,
+		"file":           &t.File,
+		"added":          &t.Added,
+		"removed":        &t.Removed,
+		"bar":            &t.Bar,
+		"bar-background": &t.BarBackground,
+	}
+}
+
+// validColor reports whether value is an ANSI 256-colour number or a hex
+// code
+func validColor(value string) bool {
+	if n, err := strconv.Atoi(value); err == nil {
+		return n >= 0 && n <= 255
+	}
+	hex := strings.TrimPrefix(value, "#")
+	if hex == value || (len(hex) != 3 && len(hex) != 6) {
+		return false
+	}
+	_, err := strconv.ParseUint(hex, 16, 32)
+	return err == nil
+}
+
+// themeNames returns the names of the built-in themes, sorted
+func themeNames() []string {
+	names := make([]string, 0, len(Themes))
+	for name := range Themes {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
\ No newline at end of file
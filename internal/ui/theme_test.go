@@ -0,0 +1,43 @@
+package ui
+
+import (
+	"testing"
+	
+	"github.com/charmbracelet/lipgloss"
+)
+
+func TestLoadTheme(t *testing.T) {
+	theme, err := LoadTheme("", nil)
+	if err != nil || theme != Themes["dark"] {
+		t.Errorf("Expected the dark theme by default, got %+v (%v)", theme, err)
+	}
+	
+	theme, err = LoadTheme("light", map[string]string{"count": "#ff8700", "bar-background": "254"})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if theme.Count != lipgloss.Color("#ff8700") || theme.BarBackground != lipgloss.Color("254") {
+		t.Errorf("Expected the replaced colours, got %+v", theme)
+	}
+	if theme.Text != Themes["light"].Text {
+		t.Errorf("Expected the light theme's other colours, got %+v", theme)
+	}
+	if Themes["light"].Count == theme.Count {
+		t.Error("Expected the built-in theme to be left unchanged")
+	}
+	
+	for _, tt := range []struct {
+		name   string
+		colors map[string]string
+	}{
+		{"solarized", nil},
+		{"dark", map[string]string{"background": "0"}},
+		{"dark", map[string]string{"count": "256"}},
+		{"dark", map[string]string{"count": "#ff87"}},
+		{"dark", map[string]string{"count": "orange"}},
+	} {
+		if _, err := LoadTheme(tt.name, tt.colors); err == nil {
+			t.Errorf("Expected error for %s %v, got nil", tt.name, tt.colors)
+		}
+	}
+}
\ No newline at end of file
@@ -0,0 +1,196 @@
+package watch
+
+import (
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+	
+	"github.com/fsnotify/fsnotify"
+	"github.com/user/loctree/internal/filter"
+	"github.com/user/loctree/internal/ignore"
+)
+
+// Delay is how long the watcher waits after a change for more changes
+// before reporting them together
+const Delay = 200 * time.Millisecond
+
+// Watcher reports the paths that change below a directory. Notifications
+// are not recursive, so every directory the filter allows is watched,
+// including directories created later.
+type Watcher struct {
+	root    string
+	filter  *filter.Filter
+	walk    *filter.Walk // Applies the filter, keeping ignore files read between events
+	fsw     *fsnotify.Watcher
+	changes chan []string
+}
+
+// New starts watching root and the directories below it that f allows
+// (nil for the defaults)
+func New(root string, f *filter.Filter) (*Watcher, error) {
+	fsw, err := fsnotify.NewWatcher()
+	if err != nil {
+		return nil, err
+	}
+	
+	w := &Watcher{
+		root:    root,
+		filter:  f,
+		walk:    f.Begin(root),
+		fsw:     fsw,
+		changes: make(chan []string),
+	}
+	if err := w.addTree(root); err != nil {
+		fsw.Close()
+		return nil, err
+	}
+	
+	go w.run()
+	return w, nil
+}
+
+// Changes delivers batches of changed paths, slash-separated and relative to
+// the root. A batch holding "." means the whole tree must be rescanned,
+// e.g. because notifications were lost. The channel is closed by Close.
+func (w *Watcher) Changes() <-chan []string {
+	return w.changes
+}
+
+// Close stops watching
+func (w *Watcher) Close() error {
+	return w.fsw.Close()
+}
+
+// addTree watches dir, which the filter has allowed, and the directories
+// below it that the filter allows
+func (w *Watcher) addTree(dir string) error {
+	return filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil || !d.IsDir() {
+			return nil // Skip errors and files
+		}
+		if path != dir && !w.walk.Allow(path, true) {
+			return filepath.SkipDir
+		}
+		return w.fsw.Add(path)
+	})
+}
+
+// run collects notifications into batches, sending each once no change
+// has arrived for Delay and the previous batch has been taken
+func (w *Watcher) run() {
+	defer close(w.changes)
+	
+	pending := map[string]bool{}
+	var timer <-chan time.Time
+	var out chan []string // Set while a batch is ready to send
+	var batch []string
+	
+	for {
+		select {
+		case event, ok := <-w.fsw.Events:
+			if !ok {
+				return
+			}
+			if relPath, ok := w.handle(event); ok {
+				pending[relPath] = true
+				timer = time.After(Delay)
+			}
+			
+		case _, ok := <-w.fsw.Errors:
+			if !ok {
+				return
+			}
+			// Notifications were probably dropped, so rescan everything
+			pending["."] = true
+			timer = time.After(Delay)
+			
+		case <-timer:
+			timer = nil
+			for _, sent := range batch {
+				pending[sent] = true
+			}
+			batch = batch[:0]
+			for relPath := range pending {
+				batch = append(batch, relPath)
+			}
+			sort.Strings(batch)
+			pending = map[string]bool{}
+			out = w.changes
+			
+		case out <- batch:
+			batch, out = nil, nil
+		}
+	}
+}
+
+// handle watches directories created below the root and returns the
+// changed path relative to the root, or false for events that cannot
+// change any counts, like those of filtered-out files
+func (w *Watcher) handle(event fsnotify.Event) (string, bool) {
+	if event.Op == fsnotify.Chmod {
+		return "", false
+	}
+	relPath, err := filepath.Rel(w.root, event.Name)
+	if err != nil {
+		return "", false
+	}
+	if w.isIgnoreFile(event.Name) {
+		return w.handleIgnoreFile(filepath.Dir(event.Name))
+	}
+	
+	// Removed paths are checked as files, so removing an ignored directory
+	// is still reported; rescanning it changes nothing
+	info, err := os.Lstat(event.Name)
+	isDir := err == nil && info.IsDir()
+	created := isDir && event.Op.Has(fsnotify.Create)
+	if created {
+		// A directory moved in brings its own ignore files
+		w.walk.Reload(event.Name)
+	}
+	if !w.walk.AllowPath(event.Name, isDir) {
+		return "", false
+	}
+	
+	if created {
+		if err := w.addTree(event.Name); err != nil {
+			// Changes below it would go unnoticed, so rescan everything
+			return ".", true
+		}
+	}
+	return filepath.ToSlash(relPath), true
+}
+
+// isIgnoreFile reports whether path is an ignore file the filter respects
+func (w *Watcher) isIgnoreFile(path string) bool {
+	if w.filter != nil && w.filter.NoIgnore {
+		return false
+	}
+	name := filepath.Base(path)
+	for _, ignoreFile := range ignore.FileNames {
+		if name == ignoreFile {
+			return true
+		}
+	}
+	return false
+}
+
+// handleIgnoreFile returns the directory of a changed ignore file, whose
+// rules may have included or excluded anything below it, and watches the
+// directories that became allowed
+func (w *Watcher) handleIgnoreFile(dir string) (string, bool) {
+	w.walk.Reload(dir)
+	if !w.walk.AllowPath(dir, true) {
+		return "", false
+	}
+	if err := w.addTree(dir); err != nil {
+		return ".", true
+	}
+	
+	relPath, err := filepath.Rel(w.root, dir)
+	if err != nil {
+		return "", false
+	}
+	return filepath.ToSlash(relPath), true
+}
\ No newline at end of file
@@ -0,0 +1,126 @@
+package watch
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+	
+	"github.com/user/loctree/internal/testutil"
+)
+
+// nextBatch waits for the next batch of changes
+func nextBatch(t *testing.T, w *Watcher) []string {
+	t.Helper()
+	select {
+	case batch := <-w.Changes():
+		return batch
+	case <-time.After(5 * time.Second):
+		t.Fatal("Timed out waiting for changes")
+		return nil
+	}
+}
+
+func TestWatcher_ReportsChangesInBatches(t *testing.T) {
+	root := t.TempDir()
+	if err := os.Mkdir(filepath.Join(root, "src"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	w, err := New(root, nil)
+	if err != nil {
+		t.Fatalf("Error watching: %v", err)
+	}
+	defer w.Close()
+	
+	testutil.WriteFile(t, filepath.Join(root, "src", "main.go"), "package main\n")
+	testutil.WriteFile(t, filepath.Join(root, "README.md"), "# Readme\n")
+	if got := strings.Join(nextBatch(t, w), " "); got != "README.md src/main.go" {
+		t.Errorf("Expected README.md src/main.go, got %s", got)
+	}
+}
+
+func TestWatcher_WatchesNewDirectories(t *testing.T) {
+	root := t.TempDir()
+	w, err := New(root, nil)
+	if err != nil {
+		t.Fatalf("Error watching: %v", err)
+	}
+	defer w.Close()
+	
+	if err := os.Mkdir(filepath.Join(root, "pkg"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if got := strings.Join(nextBatch(t, w), " "); got != "pkg" {
+		t.Errorf("Expected pkg, got %s", got)
+	}
+	
+	testutil.WriteFile(t, filepath.Join(root, "pkg", "pkg.go"), "package pkg\n")
+	if got := strings.Join(nextBatch(t, w), " "); got != "pkg/pkg.go" {
+		t.Errorf("Expected pkg/pkg.go, got %s", got)
+	}
+}
+
+func TestWatcher_SkipsFilteredPaths(t *testing.T) {
+	root := t.TempDir()
+	testutil.WriteFile(t, filepath.Join(root, ".gitignore"), "*.log\n")
+	w, err := New(root, nil)
+	if err != nil {
+		t.Fatalf("Error watching: %v", err)
+	}
+	defer w.Close()
+	
+	testutil.WriteFile(t, filepath.Join(root, "debug.log"), "noise\n")
+	testutil.WriteFile(t, filepath.Join(root, ".swap"), "noise\n")
+	testutil.WriteFile(t, filepath.Join(root, "main.go"), "package main\n")
+	if got := strings.Join(nextBatch(t, w), " "); got != "main.go" {
+		t.Errorf("Expected only main.go, got %s", got)
+	}
+}
+
+func TestWatcher_IgnoreFileChangesItsDirectory(t *testing.T) {
+	root := t.TempDir()
+	for _, dir := range []string{"src", "src/gen"} {
+		if err := os.Mkdir(filepath.Join(root, dir), 0755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	testutil.WriteFile(t, filepath.Join(root, "src", ".gitignore"), "gen/\n")
+	w, err := New(root, nil)
+	if err != nil {
+		t.Fatalf("Error watching: %v", err)
+	}
+	defer w.Close()
+	
+	// Un-ignoring gen rescans src and starts watching gen
+	testutil.WriteFile(t, filepath.Join(root, "src", ".gitignore"), "")
+	if got := strings.Join(nextBatch(t, w), " "); got != "src" {
+		t.Errorf("Expected src, got %s", got)
+	}
+	testutil.WriteFile(t, filepath.Join(root, "src", "gen", "gen.go"), "package gen\n")
+	if got := strings.Join(nextBatch(t, w), " "); got != "src/gen/gen.go" {
+		t.Errorf("Expected src/gen/gen.go, got %s", got)
+	}
+	
+	testutil.WriteFile(t, filepath.Join(root, ".loctreeignore"), "*.md\n")
+	if got := strings.Join(nextBatch(t, w), " "); got != "." {
+		t.Errorf("Expected the root, got %s", got)
+	}
+}
+
+func TestWatcher_CloseEndsChanges(t *testing.T) {
+	w, err := New(t.TempDir(), nil)
+	if err != nil {
+		t.Fatalf("Error watching: %v", err)
+	}
+	w.Close()
+	
+	select {
+	case _, ok := <-w.Changes():
+		if ok {
+			t.Error("Expected no more changes after Close")
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("Timed out waiting for the changes to end")
+	}
+}
\ No newline at end of file